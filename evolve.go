@@ -0,0 +1,67 @@
+package main
+
+import (
+	"board-game-course/model"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+)
+
+func evolve(args []string) {
+
+	defaults := model.DefaultEvolutionConfig()
+
+	flags := flag.NewFlagSet("evolve", flag.ExitOnError)
+	population := flags.Int("population", defaults.PopulationSize, "number of weight vectors in each generation")
+	generations := flags.Int("generations", defaults.Generations, "generation to stop at")
+	games := flags.Int("games", defaults.GamesPerEval, "games played by each candidate per generation")
+	players := flags.Int("players", defaults.PlayersPerGame, "players per game")
+	mutation := flags.Float64("mutation", defaults.MutationScale, "relative size of the mutations")
+	elite := flags.Int("elite", defaults.Elite, "best candidates copied unchanged to the next generation")
+	seed := flags.Int64("seed", time.Now().UnixNano(), "seed of a new run")
+	checkpoint := flags.String("checkpoint", defaults.Checkpoint, "file where every generation is saved")
+	resume := flags.Bool("resume", false, "continue from the checkpoint file")
+	output := flags.String("output", HeuristicWeightsFile, "file where the best weights are written")
+	flags.Parse(args)
+
+	if !*resume {
+		fmt.Printf("Evolution seed: %d\n", *seed)
+	}
+
+	result, err := model.Evolve(model.EvolutionConfig{
+		PopulationSize: *population,
+		Generations:    *generations,
+		GamesPerEval:   *games,
+		PlayersPerGame: *players,
+		MutationScale:  *mutation,
+		Elite:          *elite,
+		Seed:           *seed,
+		Checkpoint:     *checkpoint,
+	},
+		*resume,
+		os.Stdout,
+		NumberOfPanicCardsToActivateEffect,
+		NumberOfItemSlots,
+		NumberOfAmuletsToWin,
+		MaxRounds,
+	)
+	if err != nil {
+		fmt.Printf("Cannot run evolution: %s\n", err)
+		os.Exit(1)
+	}
+
+	//Without an evaluated generation the best weights are still zero
+	if result.Generation == 0 {
+		fmt.Printf("No generation evaluated, '%s' is left as it is\n", *output)
+		os.Exit(1)
+	}
+
+	err = result.Best.Save(*output)
+	if err != nil {
+		fmt.Printf("Cannot save weights: %s\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Best weights written to '%s'\n", *output)
+}
@@ -1,22 +1,83 @@
 package main
 
-import "board-game-course/model"
+import (
+	"board-game-course/model"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+)
 
 var NumberOfPlayers = model.NewGameParameter(model.NumberOfPlayers, 2)
 var NumberOfPanicCardsToActivateEffect = model.NewGameParameter(model.NumberOfPanicCardsToActivateEffect, 3)
 var NumberOfItemSlots = model.NewGameParameter(model.NumberOfItemSlots, 3)
 var NumberOfAmuletsToWin = model.NewGameParameter(model.NumberOfAmuletsToWin, 3)
+var RotateSeats = model.NewGameParameter(model.RotateSeats, 1)
+var MaxRounds = model.NewGameParameter(model.MaxRounds, 100)
 
 var NumberOfGames = 1
 
+var NumberOfHumanPlayers = 1
+var HeuristicWeightsFile = "heuristic.json"
+var ScriptFile = ""
+
 func main() {
 
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "tournament":
+			tournament(os.Args[2:])
+			return
+		case "evolve":
+			evolve(os.Args[2:])
+			return
+		case "simulate":
+			simulate(os.Args[2:])
+			return
+		}
+	}
+
+	flags := flag.NewFlagSet("play", flag.ExitOnError)
+	seed := flags.Int64("seed", time.Now().UnixNano(), "seed of the session, reuse it to replay the same games")
+	scriptFile := flags.String("script", ScriptFile, "file of answers played by the human players instead of the terminal")
+	undo := flags.Bool("undo", false, "let the human players undo and redo decisions with Z and R, even after cards were drawn")
+	flags.Parse(os.Args[1:])
+
 	game := model.NewGame(
 		NumberOfPlayers,
 		NumberOfPanicCardsToActivateEffect,
 		NumberOfItemSlots,
 		NumberOfAmuletsToWin,
+		RotateSeats,
+		MaxRounds,
+		model.NewGameParameter(model.Seed, int(*seed)),
 	)
 
+	weights, err := model.LoadHeuristicWeights(HeuristicWeightsFile)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		fmt.Printf("Cannot load heuristic weights: %s\n", err)
+		os.Exit(1)
+	}
+
+	if *scriptFile != "" {
+		script, err := model.LoadScriptedController(*scriptFile)
+		if err != nil {
+			fmt.Printf("Cannot load script: %s\n", err)
+			os.Exit(1)
+		}
+		for i := 0; i < NumberOfHumanPlayers; i++ {
+			game.SetController(i, script)
+		}
+	}
+
+	for i := NumberOfHumanPlayers; i < NumberOfPlayers.Value(); i++ {
+		game.SetController(i, model.NewHeuristicController(weights))
+	}
+
+	if *undo {
+		game.EnableUndo(true)
+	}
+
 	game.Run(NumberOfGames)
 }
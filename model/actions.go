@@ -1,5 +1,10 @@
 package model
 
+import (
+	"fmt"
+	"maps"
+)
+
 type actionType string
 
 const (
@@ -26,9 +31,33 @@ type action struct {
 	params     map[actionParam]int
 }
 
+func (a action) clone() action {
+	a.params = maps.Clone(a.params)
+	return a
+}
+
 func NewAction(actionType actionType, params map[actionParam]int) action {
 	return action{
 		actionType: actionType,
 		params:     params,
 	}
 }
+
+// sameAs compares the actions the way actionKey does without formatting
+// them, the validation of every action played relies on it
+func (a action) sameAs(b action) bool {
+	if a.actionType != b.actionType {
+		return false
+	}
+	for _, param := range []actionParam{DiveLevels, AscendLevels, ExploreTime, ItemToUse} {
+		if a.params[param] != b.params[param] {
+			return false
+		}
+	}
+	return true
+}
+
+// actionKey tells actions apart by their type and their parameters
+func actionKey(a action) string {
+	return fmt.Sprintf("%s %d %d %d %d", a.actionType, a.params[DiveLevels], a.params[AscendLevels], a.params[ExploreTime], a.params[ItemToUse])
+}
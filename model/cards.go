@@ -20,10 +20,16 @@ type genericCard struct {
 func (c genericCard) GetName() string   { return c.Name }
 func (c genericCard) GetType() cardType { return c.Type }
 
-func toCardSlice[T card](input []T) []card {
+type cloneableCard[T any] interface {
+	card
+	clone() T
+}
+
+// Every deck gets its own copy of the cards so that games never share data
+func toCardSlice[T cloneableCard[T]](input []T) []card {
 	res := make([]card, len(input))
 	for i, v := range input {
-		res[i] = v
+		res[i] = v.clone()
 	}
 	return res
 }
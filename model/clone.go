@@ -0,0 +1,71 @@
+package model
+
+import (
+	"maps"
+	"math/rand"
+)
+
+// randomSource is a splitmix64 generator, unlike the sources of math/rand
+// its whole state is one number so a game can copy it when it is cloned
+type randomSource struct {
+	state uint64
+}
+
+func newRandomSource(seed int64) *randomSource {
+	return &randomSource{state: uint64(seed)}
+}
+
+func (s *randomSource) Seed(seed int64) {
+	s.state = uint64(seed)
+}
+
+func (s *randomSource) Uint64() uint64 {
+	s.state += 0x9e3779b97f4a7c15
+	z := s.state
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
+
+func (s *randomSource) Int63() int64 {
+	return int64(s.Uint64() >> 1)
+}
+
+// A cloneableController gives a copy of itself for a cloned game, random
+// numbers included. The other controllers, like the terminal of a human
+// player, are shared with the clone.
+type cloneableController interface {
+	clone() PlayerController
+}
+
+// Clone copies the game so that it can be played on without changing the
+// original one. The game and the cloneable controllers go on from a copy of
+// their random sources, so the clone plays out like the original would. It
+// has no subscribers nor hooks so nothing it does is reported.
+func (g *game) Clone() game {
+
+	source := *g.source
+
+	clone := game{
+		state:       g.state.Clone(),
+		parameters:  NewGameParameters(nil),
+		source:      &source,
+		randomizer:  rand.New(&source),
+		console:     &consoleLogger{out: g.console.out},
+		controllers: make(map[string]PlayerController, len(g.controllers)),
+		turn:        g.turn.clone(),
+	}
+	maps.Copy(clone.parameters.values, g.parameters.values)
+
+	for id, controller := range g.controllers {
+		if cloneable, ok := controller.(cloneableController); ok {
+			controller = cloneable.clone()
+		}
+		clone.controllers[id] = controller
+	}
+	for i := range clone.state.Players {
+		clone.state.Players[i].Controller = clone.controllers[clone.state.Players[i].Id]
+	}
+
+	return clone
+}
@@ -0,0 +1,24 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCloneOfRandomBotsPlaysLikeTheOriginal(t *testing.T) {
+
+	for seed := range 20 {
+		g := newRandomGame(seed)
+		for range 40 {
+			g.Step()
+		}
+
+		clone := g.Clone()
+		g.play()
+		clone.play()
+
+		if original, cloned := g.Result(), clone.Result(); !reflect.DeepEqual(original, cloned) {
+			t.Errorf("seed %d: clone ended with %+v, original with %+v", seed, cloned, original)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package model
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// consoleLogger prints the events of a game as the turn log of the console
+type consoleLogger struct {
+	out io.Writer
+}
+
+func (c *consoleLogger) printf(format string, a ...any) {
+	if c.out == io.Discard {
+		return
+	}
+	fmt.Fprintf(c.out, format, a...)
+}
+
+func (c *consoleLogger) printCards(cards []card, message string) {
+	c.printf("%s\n", message)
+	for _, card := range cards {
+		c.printf("\t\t%s\n", card.GetName())
+	}
+}
+
+func (c *consoleLogger) printInventory(items []item) {
+	c.printf("\tInventory:\n")
+	for _, item := range items {
+		c.printf("\t\t%s\n", item.name)
+	}
+}
+
+func (c *consoleLogger) handle(event Event) {
+	if c.out == io.Discard {
+		return
+	}
+
+	switch e := event.(type) {
+	case SessionStarted:
+		c.printf("Seed: %d\n", e.Seed)
+	case GameStarted:
+		if e.Games > 1 {
+			c.printf("Start game: %d of %d\n", e.Game, e.Games)
+		}
+	case RoundStarted:
+		c.printf("Start round: %d\n", e.Round)
+	case TurnStarted:
+		c.printf("Player '%s':\n", e.Player)
+		c.printf("\tStart turn\n")
+		c.printf("\tLevel %d\n", e.Level)
+		c.printInventory(e.Inventory)
+	case Breathed:
+		c.printCards(e.Cards, "\tBreath:\n")
+		c.printCards(e.Hand, "\tHand:\n")
+	case CardsDrawn:
+		c.printf("\t[LOG] %s: drawn %d cards from oxygen deck\n", e.Cause, len(e.Cards))
+	case PanicActivated:
+		c.printf("\tActivate level %d of '%s' panic type\n", e.Level, e.PanicType)
+	case PanicCancelled:
+		c.printf("\t[LOG] Player '%s' used '%s' to cancel '%s' panic type\n", e.Player, e.Item.name, e.PanicType)
+	case EffectApplied:
+		c.printf("\tApply effect: %s (value: %d)\n", e.Effect.effectType, e.Effect.value)
+	case ActionsAvailable:
+		c.printf("\tAvailable Actions: %+v\n", e.Actions)
+	case ActionChosen:
+		c.printf("\tAction To Do: %+v\n", e.Action)
+		for i, ranked := range e.Explanation {
+			if i == explanationLength {
+				break
+			}
+			c.printf("\t\t%d. %s [%.2f]\n", i+1, ranked.Rationale, ranked.Score)
+		}
+	case ActionRejected:
+		c.printf("\tAction rejected: %s\n", e.Err)
+	case LevelChanged:
+		c.printf("\t[LOG] Player '%s' level changed from %d to %d\n", e.Player, e.From, e.To)
+	case ItemFound:
+		if e.Slot < 0 {
+			c.printf("\t[LOG] Item '%s' was not placed (all slots full and player declined all replacements)\n", e.Item.name)
+		} else {
+			c.printf("\t[LOG] Item '%s' placed in empty inventory slot %d\n", e.Item.name, e.Slot)
+		}
+	case ItemReplaced:
+		c.printf("\t[LOG] Replacing item '%s' with '%s' in slot %d\n", e.Dropped.name, e.Item.name, e.Slot)
+	case ItemDropped:
+		c.printf("\t[LOG] Player '%s' dropped item '%s' (%s)\n", e.Player, e.Item.name, e.Cause)
+	case ItemUsed:
+		c.printf("\t[LOG] UseObject action: using item '%s' (slot %d)\n", e.Item.name, e.Slot)
+	case CostModified:
+		c.printf("\t[LOG] %s cost of player '%s': %d", strings.ToLower(string(e.Cost)), e.Player, e.Base)
+		reduced := e.Base
+		for _, m := range e.Modifiers {
+			c.printf(", %s", m)
+			reduced -= m.value
+		}
+		if reduced != e.Modified {
+			c.printf(", minimum %d", e.Modified)
+		}
+		c.printf(" = %d\n", e.Modified)
+	case O2CardsLooked:
+		c.printf("\t[LOG] Player '%s' looked at the next %d oxygen cards\n", e.Player, e.Count)
+	case ItemEffectUnsupported:
+		c.printf("\t[LOG] %s NOT IMPLEMENTED\n", e.Effect.effectType)
+	case PanicCardDiscarded:
+		if e.Forced {
+			c.printf("\t[LOG] Must discard, discarding panic card '%s' (%d/%d discarded)\n", e.Card.GetName(), e.Discarded, e.Required)
+		} else {
+			c.printf("\t[LOG] Discarding panic card '%s' (%d/%d discarded)\n", e.Card.GetName(), e.Discarded, e.Required)
+		}
+	case PlayerDistracted:
+		c.printf("\t[LOG] Player '%s' distracted '%s', CantExplore for %d turns\n", e.Player, e.Target, e.Turns)
+	case PlayerBlocked:
+		c.printf("\t[LOG] Player '%s' caught '%s' with '%s', CantMove for %d turns\n", e.Player, e.Target, e.Item.name, e.Turns)
+	case HistoryChanged:
+		c.printf("\t[LOG] %s by player '%s': round %d, turn of '%s'\n", e.Request, e.Player, e.Round, e.Next)
+	case HistoryRejected:
+		c.printf("\tCannot %s: %s\n", strings.ToLower(string(e.Request)), e.Err)
+	case ActionResolved:
+		c.printf("\tAction resolved\n")
+		c.printInventory(e.Inventory)
+		c.printCards(e.Hand, "\tHand:\n")
+	case TurnEnded:
+		c.printf("\tEnd turn\n\n")
+	case PlayerDied:
+		c.printf("Player '%s' died at level %d in round %d\n\n", e.Player, e.Level, e.Round)
+	case RoundEnded:
+		c.printf("End round: %d\n", e.Round)
+	case GameEnded:
+		switch e.Result.EndReason {
+		case DavyJonesKilled:
+			c.printf("Davy Jones is dead after %d rounds, winners: %v\n", e.Result.Rounds, e.Result.Winners)
+		case RoundLimitReached:
+			c.printf("The round limit is reached after %d rounds, winners: %v\n", e.Result.Rounds, e.Result.Winners)
+		default:
+			c.printf("Every diver is dead after %d rounds, winners: %v\n", e.Result.Rounds, e.Result.Winners)
+		}
+		for _, player := range e.Result.Players {
+			c.printf("\t%s: level %d, oxygen %d, items %v, %d panic activations", player.Id, player.Level, player.Oxygen, player.Items, player.PanicActivations)
+			if player.Dead {
+				c.printf(", died from %s", player.CauseOfDeath)
+			}
+			c.printf("\n")
+		}
+	case SessionEnded:
+		c.printf("Results after %d games (%.1f rounds on average):\n", e.Games, float64(e.Rounds)/float64(e.Games))
+		for _, result := range e.Results {
+			c.printf("\t%s: wins %.1f (%.0f%%), deaths %d, average score %.1f\n",
+				result.Player,
+				result.Wins,
+				100*result.Wins/float64(result.Games),
+				result.Deaths,
+				float64(result.Score)/float64(result.Games),
+			)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package model
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+)
+
+type PlayerController interface {
+	DecideAction(gameState state, p player, availableActions []actionType) action
+	DecideReplaceItem(gameState state, p player, newItem item, slot int) bool
+	DecideDiscardPanicCard(gameState state, p player, panicCard card) bool
+	//DecideTarget returns the index of the diver chosen among the targets
+	DecideTarget(gameState state, p player, usedItem item, targets []player) int
+	//DecideCancelPanic is asked while the panic is about to activate
+	DecideCancelPanic(gameState state, p player, usedItem item, panicType panicType) bool
+}
+
+// An O2CardObserver is shown the top cards of the oxygen deck its player
+// looked at, the other controllers find them through KnownO2Cards
+type O2CardObserver interface {
+	ObserveO2Cards(gameState state, p player, cards []card)
+}
+
+type ControllerFactory func(randomizer *rand.Rand) PlayerController
+
+var registeredControllers = map[string]ControllerFactory{
+	"random": func(randomizer *rand.Rand) PlayerController {
+		return NewRandomController(randomizer)
+	},
+	"heuristic": func(randomizer *rand.Rand) PlayerController {
+		return NewHeuristicController(DefaultHeuristicWeights())
+	},
+	"mcts": func(randomizer *rand.Rand) PlayerController {
+		return NewMCTSController(DefaultMCTSConfig(), randomizer)
+	},
+	"ismcts": func(randomizer *rand.Rand) PlayerController {
+		return NewInformationSetController(DefaultMCTSConfig(), randomizer)
+	},
+	"omniscient": func(randomizer *rand.Rand) PlayerController {
+		return NewOmniscientController(DefaultMCTSConfig(), randomizer)
+	},
+}
+
+func RegisterController(name string, factory ControllerFactory) {
+	registeredControllers[name] = factory
+}
+
+func RegisteredControllers() []string {
+	names := make([]string, 0, len(registeredControllers))
+	for name := range registeredControllers {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+func NewRegisteredController(name string, randomizer *rand.Rand) (PlayerController, error) {
+	factory, found := registeredControllers[name]
+	if !found {
+		return nil, fmt.Errorf("unknown controller '%s', registered controllers are %v", name, RegisteredControllers())
+	}
+	return factory(randomizer), nil
+}
@@ -0,0 +1,253 @@
+package model
+
+// Event is something that happened in the game, subscribers switch on its
+// type to pick the events they care about
+type Event interface {
+	event()
+}
+
+type EventHandler func(Event)
+
+type SessionStarted struct {
+	Seed int
+}
+
+type GameStarted struct {
+	Game  int
+	Games int
+}
+
+type RoundStarted struct {
+	Round int
+}
+
+type TurnStarted struct {
+	Player    string
+	Level     int
+	Inventory []item
+}
+
+type Breathed struct {
+	Player string
+	Cards  []card
+	Hand   []card
+}
+
+type CardsDrawn struct {
+	Player string
+	Cause  string
+	Cards  []card
+}
+
+type PanicActivated struct {
+	Player    string
+	Level     int
+	PanicType panicType
+}
+
+type PanicCancelled struct {
+	Player    string
+	PanicType panicType
+	Item      item
+}
+
+type EffectApplied struct {
+	Player string
+	Effect panicEffect
+}
+
+type ActionsAvailable struct {
+	Player  string
+	Actions []actionType
+}
+
+type ActionChosen struct {
+	Player      string
+	Action      action
+	Explanation []RankedAction
+}
+
+type ActionRejected struct {
+	Player string
+	Action action
+	Err    error
+}
+
+type LevelChanged struct {
+	Player string
+	From   int
+	To     int
+}
+
+type ItemFound struct {
+	Player string
+	Item   item
+	//Slot is -1 when the item was left behind
+	Slot int
+}
+
+type ItemReplaced struct {
+	Player  string
+	Dropped item
+	Item    item
+	Slot    int
+}
+
+type ItemDropped struct {
+	Player string
+	Item   item
+	Cause  string
+}
+
+type ItemUsed struct {
+	Player string
+	Item   item
+	Slot   int
+}
+
+type CostModified struct {
+	Player    string
+	Cost      costType
+	Base      int
+	Modified  int
+	Modifiers []modifier
+}
+
+type O2CardsLooked struct {
+	Player string
+	Count  int
+}
+
+type ItemEffectUnsupported struct {
+	Player string
+	Effect itemEffect
+}
+
+type PanicCardDiscarded struct {
+	Player string
+	Card   card
+	//Forced is set when the player declined every card left
+	Forced    bool
+	Discarded int
+	Required  int
+}
+
+type PlayerDistracted struct {
+	Player string
+	Target string
+	Turns  int
+}
+
+type PlayerBlocked struct {
+	Player string
+	Target string
+	Item   item
+	Turns  int
+}
+
+type ActionResolved struct {
+	Player    string
+	Inventory []item
+	Hand      []card
+}
+
+type HistoryChanged struct {
+	Player  string
+	Request actionType
+	Round   int
+	//Next is the player deciding after the change
+	Next string
+}
+
+type HistoryRejected struct {
+	Player  string
+	Request actionType
+	Err     error
+}
+
+type TurnEnded struct {
+	Player string
+}
+
+type PlayerDied struct {
+	Player string
+	Level  int
+	Round  int
+}
+
+type RoundEnded struct {
+	Round int
+}
+
+type GameEnded struct {
+	Game   int
+	Result GameResult
+}
+
+type SessionEnded struct {
+	Games   int
+	Rounds  int
+	Results []SessionResult
+}
+
+type SessionResult struct {
+	Player string
+	Games  int
+	Wins   float64
+	Deaths int
+	Score  int
+}
+
+func (SessionStarted) event()        {}
+func (GameStarted) event()           {}
+func (RoundStarted) event()          {}
+func (TurnStarted) event()           {}
+func (Breathed) event()              {}
+func (CardsDrawn) event()            {}
+func (PanicActivated) event()        {}
+func (PanicCancelled) event()        {}
+func (EffectApplied) event()         {}
+func (ActionsAvailable) event()      {}
+func (ActionChosen) event()          {}
+func (ActionRejected) event()        {}
+func (LevelChanged) event()          {}
+func (ItemFound) event()             {}
+func (ItemReplaced) event()          {}
+func (ItemDropped) event()           {}
+func (ItemUsed) event()              {}
+func (CostModified) event()          {}
+func (O2CardsLooked) event()         {}
+func (ItemEffectUnsupported) event() {}
+func (PanicCardDiscarded) event()    {}
+func (PlayerDistracted) event()      {}
+func (PlayerBlocked) event()         {}
+func (ActionResolved) event()        {}
+func (HistoryChanged) event()        {}
+func (HistoryRejected) event()       {}
+func (TurnEnded) event()             {}
+func (PlayerDied) event()            {}
+func (RoundEnded) event()            {}
+func (GameEnded) event()             {}
+func (SessionEnded) event()          {}
+
+func (g *game) Subscribe(handler EventHandler) {
+	g.subscribers = append(g.subscribers, handler)
+}
+
+func (g *game) publish(event Event) {
+	for _, handler := range g.subscribers {
+		handler(event)
+	}
+}
+
+// inventory copies the items so that subscribers keep what the player held
+// when the event happened
+func inventory(p *player) []item {
+	items := make([]item, 0, len(p.Inventory))
+	for _, item := range p.Inventory {
+		if item != nil {
+			items = append(items, *item)
+		}
+	}
+	return items
+}
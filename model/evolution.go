@@ -0,0 +1,289 @@
+package model
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"os"
+	"slices"
+)
+
+type EvolutionConfig struct {
+	PopulationSize int
+	Generations    int
+	GamesPerEval   int
+	PlayersPerGame int
+	MutationScale  float64
+	Elite          int
+	Seed           int64
+	Checkpoint     string
+}
+
+func DefaultEvolutionConfig() EvolutionConfig {
+	return EvolutionConfig{
+		PopulationSize: 20,
+		Generations:    30,
+		GamesPerEval:   40,
+		PlayersPerGame: 4,
+		MutationScale:  0.2,
+		Elite:          2,
+		Checkpoint:     "evolution.json",
+	}
+}
+
+type EvolutionCheckpoint struct {
+	Generation  int                `json:"generation"`
+	Population  []HeuristicWeights `json:"population"`
+	Fitness     []float64          `json:"fitness"`
+	Best        HeuristicWeights   `json:"best"`
+	BestFitness float64            `json:"bestFitness"`
+	NextSeed    int64              `json:"nextSeed"`
+}
+
+func (w HeuristicWeights) vector() []float64 {
+	return []float64{w.Oxygen, w.AmuletZone, w.Panic, w.Amulets, w.AmuletsAtBottom, w.Treasure, w.Utility, w.Distract, w.Waiting}
+}
+
+func weightsFromVector(v []float64) HeuristicWeights {
+	return HeuristicWeights{
+		Oxygen:          v[0],
+		AmuletZone:      v[1],
+		Panic:           v[2],
+		Amulets:         v[3],
+		AmuletsAtBottom: v[4],
+		Treasure:        v[5],
+		Utility:         v[6],
+		Distract:        v[7],
+		Waiting:         v[8],
+	}
+}
+
+var weightNames = []string{"oxygen", "amuletZone", "panic", "amulets", "amuletsAtBottom", "treasure", "utility", "distract", "waiting"}
+
+func LoadEvolutionCheckpoint(path string) (EvolutionCheckpoint, error) {
+	var checkpoint EvolutionCheckpoint
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return checkpoint, err
+	}
+
+	err = json.Unmarshal(data, &checkpoint)
+
+	return checkpoint, err
+}
+
+func (c EvolutionCheckpoint) Save(path string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	//Write the whole file first so an interrupted run never leaves a broken checkpoint
+	err = os.WriteFile(path+".tmp", data, 0644)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(path+".tmp", path)
+}
+
+// Evolve tunes the heuristic weights with a genetic algorithm where every
+// candidate is scored by playing against the rest of its own generation.
+// A run restarts from the checkpoint when resume is set and the file exists.
+func Evolve(config EvolutionConfig, resume bool, out io.Writer, parameters ...gameParameter) (EvolutionCheckpoint, error) {
+
+	checkpoint := EvolutionCheckpoint{
+		Generation: 0,
+		NextSeed:   config.Seed,
+	}
+
+	if resume && config.Checkpoint != "" {
+		loaded, err := LoadEvolutionCheckpoint(config.Checkpoint)
+		switch {
+		case err == nil:
+			checkpoint = loaded
+			fmt.Fprintf(out, "Resuming from generation %d of '%s'\n", checkpoint.Generation, config.Checkpoint)
+		case !errors.Is(err, os.ErrNotExist):
+			return checkpoint, err
+		}
+	}
+
+	randomizer := rand.New(rand.NewSource(checkpoint.NextSeed))
+
+	if len(checkpoint.Population) == 0 {
+		checkpoint.Population = initialPopulation(config, randomizer)
+	}
+
+	if config.PlayersPerGame < 2 || config.PlayersPerGame > len(checkpoint.Population) {
+		return checkpoint, fmt.Errorf("players per game must be between 2 and the population size")
+	}
+
+	parameters = append(slices.Clone(parameters), NewGameParameter(NumberOfPlayers, config.PlayersPerGame))
+
+	for checkpoint.Generation < config.Generations {
+
+		fitness := evaluatePopulation(config, checkpoint.Population, randomizer, parameters)
+
+		//Fitness is relative to the generation, so only its own best is kept
+		best := 0
+		for i, value := range fitness {
+			if value > fitness[best] {
+				best = i
+			}
+		}
+		checkpoint.Fitness = fitness
+		checkpoint.Best = checkpoint.Population[best]
+		checkpoint.BestFitness = fitness[best]
+
+		printGeneration(out, checkpoint)
+
+		checkpoint.Population = nextGeneration(config, checkpoint.Population, fitness, randomizer)
+		checkpoint.Generation++
+		checkpoint.NextSeed = randomizer.Int63()
+		randomizer = rand.New(rand.NewSource(checkpoint.NextSeed))
+
+		if config.Checkpoint != "" {
+			err := checkpoint.Save(config.Checkpoint)
+			if err != nil {
+				return checkpoint, err
+			}
+		}
+	}
+
+	return checkpoint, nil
+}
+
+func initialPopulation(config EvolutionConfig, randomizer *rand.Rand) []HeuristicWeights {
+
+	population := []HeuristicWeights{DefaultHeuristicWeights()}
+
+	base := DefaultHeuristicWeights().vector()
+	for len(population) < config.PopulationSize {
+		population = append(population, weightsFromVector(mutate(base, 1, randomizer)))
+	}
+
+	return population
+}
+
+func evaluatePopulation(config EvolutionConfig, population []HeuristicWeights, randomizer *rand.Rand, parameters []gameParameter) []float64 {
+
+	fitness := make([]float64, len(population))
+
+	//Every candidate plays the same deals so they are compared on equal luck
+	seeds := make([]int64, config.GamesPerEval)
+	for i := range seeds {
+		seeds[i] = randomizer.Int63()
+	}
+
+	for candidate := range population {
+		for i, seed := range seeds {
+			opponents := randomizer.Perm(len(population))
+			seats := []int{candidate}
+			for _, opponent := range opponents {
+				if opponent != candidate && len(seats) < config.PlayersPerGame {
+					seats = append(seats, opponent)
+				}
+			}
+
+			//Rotate the candidate seat across the games
+			seat := i % len(seats)
+			seats[0], seats[seat] = seats[seat], seats[0]
+
+			g := NewGame(append(parameters, NewGameParameter(Seed, int(seed)))...)
+			g.SetOutput(io.Discard)
+			for j, member := range seats {
+				g.SetController(j, NewHeuristicController(population[member]))
+			}
+
+			result := g.Run(1)[0]
+			if result.IsWinner(result.Players[seat].Id) {
+				fitness[candidate] += 1 / float64(len(result.Winners))
+			}
+		}
+		fitness[candidate] /= float64(len(seeds))
+	}
+
+	return fitness
+}
+
+func nextGeneration(config EvolutionConfig, population []HeuristicWeights, fitness []float64, randomizer *rand.Rand) []HeuristicWeights {
+
+	ranking := make([]int, len(population))
+	for i := range ranking {
+		ranking[i] = i
+	}
+	slices.SortStableFunc(ranking, func(a, b int) int {
+		return cmp.Compare(fitness[b], fitness[a])
+	})
+
+	next := make([]HeuristicWeights, 0, len(population))
+	for i := 0; i < config.Elite && i < len(ranking); i++ {
+		next = append(next, population[ranking[i]])
+	}
+
+	selectParent := func() []float64 {
+		best := randomizer.Intn(len(population))
+		for range 2 {
+			challenger := randomizer.Intn(len(population))
+			if fitness[challenger] > fitness[best] {
+				best = challenger
+			}
+		}
+		return population[best].vector()
+	}
+
+	for len(next) < len(population) {
+		mother, father := selectParent(), selectParent()
+		child := make([]float64, len(mother))
+		for i := range child {
+			if randomizer.Intn(2) == 0 {
+				child[i] = mother[i]
+			} else {
+				child[i] = father[i]
+			}
+		}
+		next = append(next, weightsFromVector(mutate(child, config.MutationScale, randomizer)))
+	}
+
+	return next
+}
+
+func mutate(v []float64, scale float64, randomizer *rand.Rand) []float64 {
+	mutated := make([]float64, len(v))
+	for i, value := range v {
+		mutated[i] = value + randomizer.NormFloat64()*scale*(math.Abs(value)+1)
+	}
+	return mutated
+}
+
+// The spread of each weight across the population shows which features the
+// evolution has settled on and which ones barely affect the fitness
+func printGeneration(out io.Writer, checkpoint EvolutionCheckpoint) {
+
+	mean := 0.0
+	for _, value := range checkpoint.Fitness {
+		mean += value
+	}
+	mean /= float64(len(checkpoint.Fitness))
+
+	fmt.Fprintf(out, "Generation %d: mean fitness %.3f, best fitness %.3f\n", checkpoint.Generation+1, mean, checkpoint.BestFitness)
+
+	best := checkpoint.Best.vector()
+	for i, name := range weightNames {
+		average, spread := 0.0, 0.0
+		for _, weights := range checkpoint.Population {
+			average += weights.vector()[i]
+		}
+		average /= float64(len(checkpoint.Population))
+		for _, weights := range checkpoint.Population {
+			spread += math.Pow(weights.vector()[i]-average, 2)
+		}
+		spread = math.Sqrt(spread / float64(len(checkpoint.Population)))
+		fmt.Fprintf(out, "\t%-16s best %8.3f  mean %8.3f  stddev %8.3f\n", name, best[i], average, spread)
+	}
+}
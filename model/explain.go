@@ -0,0 +1,162 @@
+package model
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+type RankedAction struct {
+	Action    action
+	Score     float64
+	Rationale string
+}
+
+type ExplainingController interface {
+	PlayerController
+	Explain() []RankedAction
+}
+
+func rankActions(ranked []RankedAction) []RankedAction {
+	slices.SortStableFunc(ranked, func(a, b RankedAction) int {
+		return cmp.Compare(b.Score, a.Score)
+	})
+	return ranked
+}
+
+func describeAction(a action) string {
+	switch a.actionType {
+	case Dive:
+		return fmt.Sprintf("Dive %d", a.params[DiveLevels])
+	case Ascend:
+		return fmt.Sprintf("Ascend %d", a.params[AscendLevels])
+	case Explore:
+		return fmt.Sprintf("Explore %d", a.params[ExploreTime])
+	case CalmDown:
+		return "Calm down"
+	case Distract:
+		return "Distract"
+	case UseObject:
+		if slot, found := a.params[ItemToUse]; found {
+			return fmt.Sprintf("Use item %d", slot)
+		}
+		return "Hold"
+	}
+	return string(a.actionType)
+}
+
+func drawnCards(p player, a action) int {
+	switch a.actionType {
+	case Dive:
+		cost, _ := modifiedCost(p, MovementCost, a.params[DiveLevels])
+		return cost
+	case Ascend:
+		cost, _ := modifiedCost(p, MovementCost, a.params[AscendLevels])
+		return cost
+	case Explore:
+		return a.params[ExploreTime]
+	case CalmDown:
+		return 1
+	case Distract:
+		return 2
+	}
+	return 0
+}
+
+// rationale lists what the action changes for the player, the panic chance
+// only uses the composition of the oxygen deck
+func rationale(gameState state, p player, a action) string {
+
+	reasons := make([]string, 0)
+	f := forecast(gameState, p, a)
+
+	inZone := func(level int) bool { return level >= 7 && level <= 9 }
+	switch {
+	case f.level == 10 && f.amulets > 0 && p.DiveLevel != 10:
+		reasons = append(reasons, "+amulets to Davy Jones")
+	case inZone(f.level) && !inZone(p.DiveLevel):
+		reasons = append(reasons, "+amulet zone")
+	case !inZone(f.level) && inZone(p.DiveLevel):
+		reasons = append(reasons, "-amulet zone")
+	}
+
+	drawn := min(drawnCards(p, a), len(p.OxygenCards))
+	if drawn > 0 {
+		reasons = append(reasons, fmt.Sprintf("-%d O2", drawn))
+	}
+
+	switch a.actionType {
+	case Explore:
+		if f.amulets > 0 {
+			reasons = append(reasons, fmt.Sprintf("%.2f amulets expected", f.amulets))
+		}
+	case CalmDown:
+		reasons = append(reasons, "discard up to 3 panic cards")
+	case Ascend:
+		reasons = append(reasons, fmt.Sprintf("discard up to %d panic cards", a.params[AscendLevels]+1))
+	case Distract:
+		reasons = append(reasons, fmt.Sprintf("distracts %d divers", f.distracted))
+	}
+
+	if panicType, chance := activationChance(p, drawn, gameState.PanicThreshold); chance > 0 {
+		reasons = append(reasons, fmt.Sprintf("%.0f%% chance of %s panic", 100*chance, strings.ToLower(string(panicType))))
+	}
+
+	if len(reasons) == 0 {
+		return describeAction(a)
+	}
+
+	return fmt.Sprintf("%s: %s", describeAction(a), strings.Join(reasons, ", "))
+}
+
+// activationChance returns the panic type most likely to activate when the
+// player draws the given number of cards and its probability, a panic type
+// activates with threshold cards in hand
+func activationChance(p player, numberOfCards int, threshold int) (panicType, float64) {
+
+	var mostLikely panicType
+	bestChance := 0.0
+
+	deckSize := len(p.OxygenCards)
+	numberOfCards = min(numberOfCards, deckSize)
+
+	for _, panicType := range panicTypes {
+		inHand := 0
+		for _, card := range p.HandCards {
+			if slices.Contains(card.(panicCard).panicTypes, panicType) {
+				inHand++
+			}
+		}
+		inDeck := 0
+		for _, card := range p.OxygenCards {
+			if card.GetType() == PanicType && slices.Contains(card.(panicCard).panicTypes, panicType) {
+				inDeck++
+			}
+		}
+
+		needed := threshold - inHand
+		chance := 0.0
+		for drawn := max(needed, 0); drawn <= numberOfCards && drawn <= inDeck; drawn++ {
+			chance += binomial(inDeck, drawn) * binomial(deckSize-inDeck, numberOfCards-drawn) / binomial(deckSize, numberOfCards)
+		}
+
+		if chance > bestChance {
+			bestChance = chance
+			mostLikely = panicType
+		}
+	}
+
+	return mostLikely, bestChance
+}
+
+func binomial(n, k int) float64 {
+	if k < 0 || k > n {
+		return 0
+	}
+	result := 1.0
+	for i := 1; i <= k; i++ {
+		result = result * float64(n-k+i) / float64(i)
+	}
+	return result
+}
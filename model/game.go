@@ -1,11 +1,11 @@
 package model
 
 import (
-	"bufio"
 	"fmt"
+	"io"
 	"math/rand"
 	"os"
-	"strings"
+	"slices"
 	"time"
 )
 
@@ -16,8 +16,15 @@ const (
 	NumberOfPanicCardsToActivateEffect GameParameterType = "NUMBER_OF_PANIC_CARD_TO_ACTIVATE_EFFECT"
 	NumberOfItemSlots                  GameParameterType = "NUMBER_OF_ITEM_SLOTS"
 	NumberOfAmuletsToWin               GameParameterType = "NUMBER_OF_AMULETS_TO_WIN"
+	RotateSeats                        GameParameterType = "ROTATE_SEATS"
+	Seed                               GameParameterType = "SEED"
+	//MaxRounds stops the game after that many rounds, without it a game can
+	//go on as long as a diver stays alive
+	MaxRounds GameParameterType = "MAX_ROUNDS"
 )
 
+const explanationLength = 5
+
 type gameParameter struct {
 	parameterType GameParameterType
 	value         int
@@ -30,10 +37,22 @@ func NewGameParameter(parameterType GameParameterType, value int) gameParameter
 	}
 }
 
+func (p gameParameter) Value() int {
+	return p.value
+}
+
 type game struct {
-	state      state
-	parameters parameters
-	randomizer *rand.Rand
+	state       state
+	parameters  parameters
+	source      *randomSource
+	randomizer  *rand.Rand
+	console     *consoleLogger
+	subscribers []EventHandler
+	controllers map[string]PlayerController
+	turn        turn
+	beforePhase []PhaseHook
+	afterPhase  []PhaseHook
+	history     *history
 }
 
 type parameters struct {
@@ -56,14 +75,28 @@ type state struct {
 	Players      []player
 	Round        int
 	ActualPlayer int
+	//PanicThreshold is the number of cards of a panic type activating it
+	PanicThreshold int
 }
 
 func NewState() state {
 	return state{
-		Players:      make([]player, 0),
-		Round:        0,
-		ActualPlayer: -1,
+		Players:        make([]player, 0),
+		Round:          0,
+		ActualPlayer:   -1,
+		PanicThreshold: defaultPanicThreshold,
+	}
+}
+
+// Clone deep copies the players, the cards themselves are never changed so
+// the copied decks and hands share them with the original
+func (s state) Clone() state {
+	clone := s
+	clone.Players = make([]player, len(s.Players))
+	for i, player := range s.Players {
+		clone.Players[i] = player.clone()
 	}
+	return clone
 }
 
 func (s *state) AddPlayer(player player) {
@@ -91,109 +124,180 @@ func NewGame(
 ) game {
 
 	g := game{
-		parameters: NewGameParameters(parameters),
-		state:      NewState(),
-		randomizer: rand.New(rand.NewSource(time.Now().UnixNano())),
+		parameters:  NewGameParameters(parameters),
+		state:       NewState(),
+		console:     &consoleLogger{out: os.Stdout},
+		controllers: make(map[string]PlayerController),
+	}
+	g.Subscribe(g.console.handle)
+
+	//Without a seed every game is different, the seed is kept to replay it
+	if _, found := g.parameters.values[Seed]; !found {
+		g.parameters.values[Seed] = int(time.Now().UnixNano())
 	}
+	g.source = newRandomSource(int64(g.parameters.values[Seed]))
+	g.randomizer = rand.New(g.source)
 
+	terminal := NewTerminalController(os.Stdin)
 	for i := range g.parameters.values[NumberOfPlayers] {
-		player := NewPlayer(fmt.Sprintf("P%d", i+1), g.parameters.values[NumberOfItemSlots])
+		g.controllers[playerId(i)] = terminal
+	}
+
+	g.reset(0)
+
+	return g
+}
+
+func playerId(number int) string {
+	return fmt.Sprintf("P%d", number+1)
+}
+
+// reset deals a new game to the same players, shifting the seats by one for
+// every game played when seats rotate
+func (g *game) reset(gameNumber int) {
+
+	g.state = NewState()
+	if threshold := g.parameters.values[NumberOfPanicCardsToActivateEffect]; threshold > 0 {
+		g.state.PanicThreshold = threshold
+	}
+
+	numberOfPlayers := g.parameters.values[NumberOfPlayers]
+	for i := range numberOfPlayers {
+		number := i
+		if g.parameters.values[RotateSeats] > 0 {
+			number = (i + gameNumber) % numberOfPlayers
+		}
+
+		player := NewPlayer(playerId(number), g.parameters.values[NumberOfItemSlots])
+		player.Controller = g.controllers[player.Id]
 
 		player.OxygenCards = g.GenerateOxygenDeck()
 
 		g.state.AddPlayer(player)
 	}
 
-	return g
+	g.state.Round = 1
+	g.state.ActualPlayer = 0
+	g.turn = turn{phase: StartTurnPhase}
+
+	//The decisions of the previous game cannot be undone anymore
+	if g.history != nil {
+		g.history.undo = nil
+		g.history.redo = nil
+	}
 }
 
-func (g *game) Run(numberOfGames int) {
+// Run plays the games one after the other and returns their results
+func (g *game) Run(numberOfGames int) []GameResult {
 
-	g.state.Round = 1
+	numberOfGames = max(numberOfGames, 1)
+	results := make([]GameResult, 0, numberOfGames)
+	sessions := make(map[string]*SessionResult)
+	rounds := 0
 
-	for !g.IsGameEnded() {
-		fmt.Printf("Start round: %d\n", g.state.Round)
+	g.publish(SessionStarted{Seed: g.parameters.values[Seed]})
 
-		for i := 0; i < len(g.state.Players); i++ {
-			g.state.ActualPlayer = i
+	for gameNumber := range numberOfGames {
+		//The first game is played on the state dealt by NewGame
+		if gameNumber > 0 {
+			g.reset(gameNumber)
+		}
 
-			p := g.GetActualPlayer()
+		g.publish(GameStarted{Game: gameNumber + 1, Games: numberOfGames})
 
-			fmt.Printf("Player '%s':\n", p.Id)
+		g.play()
 
-			fmt.Printf("\tStart turn\n")
-			fmt.Printf("\tLevel %d\n", p.DiveLevel)
-			fmt.Printf("\tInventory:\n")
-			for _, item := range p.Inventory {
-				if item != nil {
-					fmt.Printf("\t\t%s\n", item.name)
-				}
-			}
+		result := g.Result()
+		results = append(results, result)
 
-			//BREATH
-			cards := p.Breath()
-			printCards(cards, "\tBreath:\n")
-			for _, card := range cards {
-				if card.GetType() == PanicType {
-					p.HandCards = append(p.HandCards, card)
-				} else {
-					p.DiscardedCards = append(p.DiscardedCards, card)
-				}
+		rounds += result.Rounds
+		for _, player := range result.Players {
+			session, found := sessions[player.Id]
+			if !found {
+				session = &SessionResult{Player: player.Id}
+				sessions[player.Id] = session
 			}
-			if p.IsDead() {
-				g.state.NextPlayer()
-				continue
+			session.Games++
+			session.Score += player.Score
+			if player.Dead {
+				session.Deaths++
+			}
+			if result.IsWinner(player.Id) {
+				session.Wins += 1 / float64(len(result.Winners))
 			}
+		}
 
-			printCards(p.HandCards, "\tHand:\n")
+		g.publish(GameEnded{Game: gameNumber + 1, Result: result})
+	}
 
-			//CHECK PANIC
-			effects := p.CheckPanic()
-			if len(effects) > 0 {
-				fmt.Printf("\tApply Effects:\n")
-				for _, effect := range effects {
-					fmt.Printf("\t\t%s", effect.effectType)
-				}
-				g.ApplyEffect(&g.state.Players[g.state.ActualPlayer], effects)
-			}
+	session := SessionEnded{
+		Games:  numberOfGames,
+		Rounds: rounds,
+	}
+	for i := range len(sessions) {
+		session.Results = append(session.Results, *sessions[playerId(i)])
+	}
+	g.publish(session)
 
-			//CHECK PLAYER EFFECTS
-			availableActions := p.CheckPlayerEffects()
-			fmt.Printf("\tAvailable Actions: %+v\n", availableActions)
+	return results
+}
 
-			//DECIDE ACTION TO DO
-			actionToDo := p.DecideActionToDo(g.state, availableActions)
-			fmt.Printf("\tAction To Do: %+v\n", actionToDo)
+func (g *game) play() {
+	for g.Step() != GameOverPhase {
+	}
+}
 
-			//RESOLVE ACTION
-			g.resolveAction(p, actionToDo)
-			fmt.Printf("\tAction resolved\n")
+func (g *game) checkPanic(p *player) {
+	resolutionOrder := slices.Clone(panicTypes)
+	g.randomizer.Shuffle(len(resolutionOrder), func(i, j int) {
+		resolutionOrder[i], resolutionOrder[j] = resolutionOrder[j], resolutionOrder[i]
+	})
 
-			fmt.Printf("\tInventory:\n")
-			for _, item := range p.Inventory {
-				if item != nil {
-					fmt.Printf("\t\t%s\n", item.name)
-				}
-			}
-			printCards(p.HandCards, "\tHand:\n")
-
-			//CHECK PANIC
-			effects = p.CheckPanic()
-			if len(effects) > 0 {
-				fmt.Printf("\tApply Effects:\n")
-				for _, effect := range effects {
-					fmt.Printf("\t\t%s\n", effect.effectType)
-				}
-				g.ApplyEffect(&g.state.Players[g.state.ActualPlayer], effects)
-			}
+	activatedPanics, effects := p.CheckPanic(g.state.PanicThreshold, resolutionOrder, func(panicType panicType) bool {
+		return g.cancelPanic(p, panicType)
+	})
+	p.PanicActivations += len(activatedPanics)
+	for _, panicType := range activatedPanics {
+		g.publish(PanicActivated{Player: p.Id, Level: p.DiveLevel, PanicType: panicType})
+	}
+	g.ApplyEffect(p, effects)
+}
 
-			fmt.Printf("\tEnd turn\n\n")
+// cancelPanic offers the first item able to stop the panic to the player,
+// the item is spent when the player takes the offer
+func (g *game) cancelPanic(p *player, panicType panicType) bool {
+	for slot, item := range p.Inventory {
+		if item == nil || !item.hasEffect(IgnorePanicActivation) {
+			continue
+		}
+		if !p.Controller.DecideCancelPanic(g.state, *p, *item, panicType) {
+			return false
 		}
+		g.publish(PanicCancelled{Player: p.Id, PanicType: panicType, Item: *item})
+		g.dropItem(p, slot, "USED")
+		return true
+	}
+	return false
+}
 
-		fmt.Printf("End round: %d\n", g.state.Round)
+// SetOutput redirects the turn log printed on the console, io.Discard
+// silences it while the other subscribers still receive the events
+func (g *game) SetOutput(out io.Writer) {
+	g.console.out = out
+}
 
-		g.state.NextRound()
-	}
+func (g *game) NewRandomizer() *rand.Rand {
+	return rand.New(newRandomSource(g.randomizer.Int63()))
+}
+
+func (g *game) SetController(playerIndex int, controller PlayerController) {
+	g.controllers[g.state.Players[playerIndex].Id] = controller
+	g.state.Players[playerIndex].Controller = controller
+}
+
+// State returns a copy of the state, changing it does not change the game
+func (g game) State() state {
+	return g.state.Clone()
 }
 
 func (g game) GetActualPlayer() *player {
@@ -206,7 +310,7 @@ func (g *game) IsDavyJonesIsDead() bool {
 	for _, player := range g.state.Players {
 		if player.DiveLevel == 10 {
 			for _, item := range player.Inventory {
-				if item.itemType == Amulets {
+				if item != nil && item.itemType == Amulets {
 					amuletAtLevel10++
 				}
 			}
@@ -233,6 +337,48 @@ func (g *game) IsGameEnded() bool {
 	return g.AreAllPlayersDead() || g.IsDavyJonesIsDead()
 }
 
+// IsRoundLimitReached tells whether every round allowed by MaxRounds was
+// played, the game stops before the next one starts
+func (g *game) IsRoundLimitReached() bool {
+	maxRounds := g.parameters.values[MaxRounds]
+	return maxRounds > 0 && g.state.Round > maxRounds
+}
+
+func (g *game) Winners() []int {
+	winners := make([]int, 0)
+
+	//The divers who brought an amulet to Davy Jones share the victory
+	if g.IsDavyJonesIsDead() {
+		for i, player := range g.state.Players {
+			if player.DiveLevel != 10 || player.IsDead() {
+				continue
+			}
+			for _, item := range player.Inventory {
+				if item != nil && item.itemType == Amulets {
+					winners = append(winners, i)
+					break
+				}
+			}
+		}
+		return winners
+	}
+
+	//Otherwise the richest divers win
+	bestScore := 1
+	for i, player := range g.state.Players {
+		score := player.Score()
+		if score > bestScore {
+			bestScore = score
+			winners = winners[:0]
+		}
+		if score == bestScore {
+			winners = append(winners, i)
+		}
+	}
+
+	return winners
+}
+
 func (g *game) GenerateOxygenDeck() []card {
 
 	deck := make([]card, 0)
@@ -270,43 +416,43 @@ func (g *game) GenerateOxygenDeck() []card {
 
 func (g *game) ApplyEffect(p *player, effects []panicEffect) {
 	for _, effect := range effects {
+		g.publish(EffectApplied{Player: p.Id, Effect: effect})
+		cause := string(effect.effectType)
+
 		switch effect.effectType {
 		case MoveUp:
-			p.DiveLevel -= effect.value
-			if p.DiveLevel < 1 {
-				p.DiveLevel = 1
-			}
+			g.moveTo(p, max(1, p.DiveLevel-effect.value))
 		case MoveDown:
-			p.DiveLevel += effect.value
-			if p.DiveLevel > 10 {
-				p.DiveLevel = 10
-			}
+			g.moveTo(p, min(10, p.DiveLevel+effect.value))
 		case CannotExplore:
 			p.ActiveEffects[CantExplore] = effect.value
 		case JumpTurn:
 			p.ActiveEffects[SkipTurn] = effect.value
 		case DiscardO2:
-			cards := p.Draw(effect.value)
+			cards := g.draw(p, effect.value, cause)
 			p.Discard(cards)
 		case DropObject:
 			for i := 0; i < effect.value && i < len(p.Inventory); i++ {
 				item := p.Inventory[i]
 				if item != nil && item.itemType == Utility {
-					p.DiscardedObjects = append(p.DiscardedObjects, *item)
-					p.Inventory[i] = nil
+					g.dropItem(p, i, cause)
 				}
 			}
 		case MustCalmDown:
 			p.ActiveEffects[HaveToCalmDown] = effect.value
 		case MoveToFreeLevel:
-			if p.DiveLevel > 1 {
-				for destinationLevel := p.DiveLevel - 1; destinationLevel <= 1; destinationLevel-- {
-					for _, player := range g.state.Players {
-						if player.Id != p.Id && player.DiveLevel == destinationLevel {
-							continue
-						}
+			for destinationLevel := p.DiveLevel - 1; destinationLevel >= 1; destinationLevel-- {
+				occupied := false
+				for _, player := range g.state.Players {
+					if player.Id != p.Id && player.DiveLevel == destinationLevel {
+						occupied = true
+						break
 					}
 				}
+				if !occupied {
+					g.moveTo(p, destinationLevel)
+					break
+				}
 			}
 
 		case DropTreasureToken:
@@ -314,8 +460,7 @@ func (g *game) ApplyEffect(p *player, effects []panicEffect) {
 			for i := 0; i < len(p.Inventory) && dropped < effect.value; i++ {
 				item := p.Inventory[i]
 				if item != nil && item.itemType == TreasureToken {
-					p.DiscardedObjects = append(p.DiscardedObjects, *item)
-					p.Inventory[i] = nil
+					g.dropItem(p, i, cause)
 					dropped++
 				}
 			}
@@ -323,48 +468,90 @@ func (g *game) ApplyEffect(p *player, effects []panicEffect) {
 			for i := 0; i < effect.value && i < len(p.Inventory); i++ {
 				item := p.Inventory[i]
 				if item != nil && item.itemType == Amulets {
-					p.DiscardedObjects = append(p.DiscardedObjects, *item)
-					p.Inventory[i] = nil
+					g.dropItem(p, i, cause)
 				}
 			}
 		case DropEverything:
 			for i := 0; i < effect.value && i < len(p.Inventory); i++ {
 				item := p.Inventory[i]
 				if item != nil {
-					p.DiscardedObjects = append(p.DiscardedObjects, *item)
-					p.Inventory[i] = nil
+					g.dropItem(p, i, cause)
 				}
 			}
 		case DropEverythingButAmulets:
 			for i := 0; i < effect.value && i < len(p.Inventory); i++ {
 				item := p.Inventory[i]
 				if item != nil && item.itemType != Amulets {
-					p.DiscardedObjects = append(p.DiscardedObjects, *item)
-					p.Inventory[i] = nil
+					g.dropItem(p, i, cause)
 				}
 			}
 		case DropO2ForSameLevelPlayers:
-			for _, player := range g.state.Players {
+			for i := range g.state.Players {
+				player := &g.state.Players[i]
 				if player.Id != p.Id && player.DiveLevel == p.DiveLevel {
-					cards := player.Draw(effect.value)
-					player.HandCards = append(player.HandCards, cards...)
+					cards := g.draw(player, effect.value, cause)
+					player.KeepPanicCards(cards)
 				}
 			}
 		case DrawO2:
-			cards := p.Draw(effect.value)
-			p.HandCards = append(p.HandCards, cards...)
+			cards := g.draw(p, effect.value, cause)
+			p.KeepPanicCards(cards)
 		}
 	}
 }
 
-func (g *game) resolveAction(p *player, action action) {
+func (g *game) moveTo(p *player, level int) {
+	if level == p.DiveLevel {
+		return
+	}
+	g.publish(LevelChanged{Player: p.Id, From: p.DiveLevel, To: level})
+	p.DiveLevel = level
+}
+
+// breath draws the oxygen the player needs at its level, reduced by what it
+// is wearing
+func (g *game) breath(p *player) []card {
+	return p.Draw(g.payCost(p, BreathingCost, BreathCost(p.DiveLevel)))
+}
+
+func (g *game) draw(p *player, numberOfCards int, cause string) []card {
+	cards := p.Draw(numberOfCards)
+	g.publish(CardsDrawn{Player: p.Id, Cause: cause, Cards: slices.Clone(cards)})
+	recordCauseOfDeath(p, cause)
+	return cards
+}
+
+// The draw that takes the last oxygen card of a diver is the cause of death
+func recordCauseOfDeath(p *player, cause string) {
+	if p.IsDead() && p.CauseOfDeath == "" {
+		p.CauseOfDeath = cause
+	}
+}
+
+func (g *game) dropItem(p *player, slot int, cause string) {
+	item := p.Inventory[slot]
+	g.publish(ItemDropped{Player: p.Id, Item: *item, Cause: cause})
+	p.DiscardedObjects = append(p.DiscardedObjects, *item)
+	p.Inventory[slot] = nil
+}
+
+func (g *game) resolveAction(p *player, action action) error {
+
+	availableActions := p.allowedActionTypes()
+	if p.Id == g.GetActualPlayer().Id && g.turn.phase == ResolutionPhase {
+		availableActions = g.turn.availableActions
+	}
+	err := validateAction(g.state, *p, availableActions, action)
+	if err != nil {
+		return err
+	}
+
+	cause := string(action.actionType)
 
 	switch action.actionType {
 
 	case Explore:
-		fmt.Printf("\t[LOG] Explore action: drawing %d cards\n", action.params[ExploreTime])
-		cards := p.Draw(action.params[ExploreTime])
-		fmt.Printf("\t[LOG] Drawn %d cards from oxygen deck\n", len(cards))
+		cards := g.draw(p, action.params[ExploreTime], cause)
 		panicCards := make([]card, 0)
 		items := make([]item, 0)
 		for _, card := range cards {
@@ -375,259 +562,193 @@ func (g *game) resolveAction(p *player, action action) {
 				items = append(items, itemCard.items[p.DiveLevel])
 			}
 		}
-		fmt.Printf("\t[LOG] Found %d panic cards and %d items (at dive level %d)\n", len(panicCards), len(items), p.DiveLevel)
-		for _, item := range items {
-			fmt.Printf("\t[LOG] Processing item: %s (type: %s)\n", item.name, item.itemType)
+		for i := range items {
+			//Point to the item of this exploration, never to a shared or reused variable
+			item := &items[i]
 			itemPlaced := false
 			for i := 0; i < len(p.Inventory); i++ {
 				slot := p.Inventory[i]
 				if slot == nil {
-					p.Inventory[i] = &item
+					p.Inventory[i] = item
 					itemPlaced = true
-					fmt.Printf("\t[LOG] Item '%s' placed in empty inventory slot %d\n", item.name, i)
+					g.publish(ItemFound{Player: p.Id, Item: *item, Slot: i})
 					break // Break after placing item in empty slot
-				} else {
-					reader := bufio.NewScanner(os.Stdin)
-
-					for {
-						fmt.Printf("\tDo you want to keep item '%s' and drop item '%s'? (Y/N): ", item.name, slot.name)
-						reader.Scan()
-						answer := strings.TrimSpace(strings.ToUpper(reader.Text()))
-
-						if answer == "Y" {
-							fmt.Printf("\t[LOG] Replacing item '%s' with '%s' in slot %d\n", slot.name, item.name, i)
-							p.Inventory[i] = &item
-							itemPlaced = true
-							break // Break from input loop and then from slot loop
-						} else if answer == "N" {
-							fmt.Printf("\t[LOG] Player declined to replace item '%s' in slot %d\n", slot.name, i)
-							break // Break from input loop, continue to next slot
-						} else {
-							fmt.Printf("\tPlease answer with Y or N.\n")
-							// Continue input loop to ask again
-						}
-					}
-					if itemPlaced {
-						break // Break from slot loop after placing item
-					}
+				} else if p.Controller.DecideReplaceItem(g.state, *p, *item, i) {
+					g.publish(ItemReplaced{Player: p.Id, Dropped: *slot, Item: *item, Slot: i})
+					p.Inventory[i] = item
+					itemPlaced = true
+					break
 				}
 			}
 			if !itemPlaced {
-				fmt.Printf("\t[LOG] Item '%s' was not placed (all slots full and player declined all replacements)\n", item.name)
+				g.publish(ItemFound{Player: p.Id, Item: *item, Slot: -1})
 			}
 		}
 
 		p.HandCards = append(p.HandCards, panicCards...)
-		fmt.Printf("\t[LOG] Added %d panic cards to hand\n", len(panicCards))
 
 	case Dive:
-		oldLevel := p.DiveLevel
-		p.DiveLevel += action.params[DiveLevels]
-		fmt.Printf("\t[LOG] Dive action: level changed from %d to %d (dived %d levels)\n", oldLevel, p.DiveLevel, action.params[DiveLevels])
-		cards := p.Draw(action.params[DiveLevels])
-		fmt.Printf("\t[LOG] Drawn %d cards from oxygen deck\n", len(cards))
-		panicCount := 0
+		g.moveTo(p, p.DiveLevel+action.params[DiveLevels])
+		cards := g.draw(p, g.payCost(p, MovementCost, action.params[DiveLevels]), cause)
 		for _, card := range cards {
 			if card.GetType() == PanicType {
 				p.HandCards = append(p.HandCards, card)
-				panicCount++
 			}
 		}
-		fmt.Printf("\t[LOG] Added %d panic cards to hand\n", panicCount)
 	case CalmDown:
-		fmt.Printf("\t[LOG] CalmDown action: drawing 1 card\n")
-		cards := p.Draw(1)
-		panicCount := 0
-		nonPanicCount := 0
-		for _, card := range cards {
-			if card.GetType() == PanicType {
-				p.HandCards = append(p.HandCards, card)
-				panicCount++
-			} else {
-				p.DiscardedCards = append(p.DiscardedCards, card)
-				nonPanicCount++
-			}
-		}
-		fmt.Printf("\t[LOG] Added %d panic cards to hand, discarded %d non-panic cards\n", panicCount, nonPanicCount)
-		discardedCard := 0
-		for discardedCard < 3 && len(p.HandCards) > 0 {
-			removed := false
-			// Iterate backwards to safely remove elements
-			for i := len(p.HandCards) - 1; i >= 0; i-- {
-				panicCard := p.HandCards[i]
-				reader := bufio.NewScanner(os.Stdin)
-
-				for {
-					fmt.Printf("\tDo you want to discard panic card '%s'? (Y/N): ", panicCard.GetName())
-					reader.Scan()
-					answer := strings.TrimSpace(strings.ToUpper(reader.Text()))
-
-					if answer == "Y" {
-						discardedCard++
-						fmt.Printf("\t[LOG] Discarding panic card '%s' (%d/%d discarded)\n", panicCard.GetName(), discardedCard, 3)
-						p.DiscardedCards = append(p.DiscardedCards, panicCard)
-						p.HandCards = append(p.HandCards[:i], p.HandCards[i+1:]...)
-						removed = true
-						break // Break from input loop
-					} else if answer == "N" {
-						break // Break from input loop to continue to next card
-					} else {
-						fmt.Printf("\tPlease answer with Y or N.\n")
-						// Continue loop to ask again
-					}
-				}
-				// If we removed a card, break to restart the outer loop
-				if removed {
-					break
-				}
-			}
-		}
-		fmt.Printf("\t[LOG] CalmDown complete: discarded %d panic cards\n", discardedCard)
+		cards := g.draw(p, 1, cause)
+		p.KeepPanicCards(cards)
+		g.discardPanicCards(p, 3)
 	case Ascend:
-		oldLevel := p.DiveLevel
-		p.DiveLevel -= action.params[AscendLevels]
-		if p.DiveLevel < 1 {
-			p.DiveLevel = 1
-		}
-		fmt.Printf("\t[LOG] Ascend action: level changed from %d to %d (ascended %d levels)\n", oldLevel, p.DiveLevel, action.params[AscendLevels])
-		cards := p.Draw(action.params[AscendLevels])
-		fmt.Printf("\t[LOG] Drawn %d cards from oxygen deck\n", len(cards))
-		panicCount := 0
+		g.moveTo(p, max(1, p.DiveLevel-action.params[AscendLevels]))
+		cards := g.draw(p, g.payCost(p, MovementCost, action.params[AscendLevels]), cause)
 		for _, card := range cards {
 			if card.GetType() == PanicType {
 				p.HandCards = append(p.HandCards, card)
-				panicCount++
-			}
-		}
-		fmt.Printf("\t[LOG] Added %d panic cards to hand\n", panicCount)
-		discardedCard := 0
-		fmt.Printf("\t[LOG] Must discard %d panic cards\n", action.params[AscendLevels]+1)
-		for discardedCard < action.params[AscendLevels]+1 && len(p.HandCards) > 0 {
-			removed := false
-			// Iterate backwards to safely remove elements
-			for i := len(p.HandCards) - 1; i >= 0; i-- {
-				panicCard := p.HandCards[i]
-				reader := bufio.NewScanner(os.Stdin)
-
-				for {
-					fmt.Printf("\tDo you want to discard panic card '%s'? (Y/N): ", panicCard.GetName())
-					reader.Scan()
-					answer := strings.TrimSpace(strings.ToUpper(reader.Text()))
-
-					if answer == "Y" {
-						discardedCard++
-						fmt.Printf("\t[LOG] Discarding panic card '%s' (%d/%d discarded)\n", panicCard.GetName(), discardedCard, action.params[AscendLevels]+1)
-						p.DiscardedCards = append(p.DiscardedCards, panicCard)
-						p.HandCards = append(p.HandCards[:i], p.HandCards[i+1:]...)
-						removed = true
-						break // Break from input loop
-					} else if answer == "N" {
-						break // Break from input loop to continue to next card
-					} else {
-						fmt.Printf("\tPlease answer with Y or N.\n")
-						// Continue loop to ask again
-					}
-				}
-				// If we removed a card, break to restart the outer loop
-				if removed {
-					break
-				}
 			}
 		}
-		fmt.Printf("\t[LOG] Ascend complete: discarded %d panic cards\n", discardedCard)
+		g.discardPanicCards(p, action.params[AscendLevels]+1)
 	case Distract:
-		fmt.Printf("\t[LOG] Distract action: drawing 2 cards\n")
-		cards := p.Draw(2)
-		panicCount := 0
+		cards := g.draw(p, 2, cause)
 		for _, card := range cards {
 			if card.GetType() == PanicType {
 				p.HandCards = append(p.HandCards, card)
-				panicCount++
 			}
 		}
-		fmt.Printf("\t[LOG] Added %d panic cards to hand\n", panicCount)
-		affectedPlayers := 0
-		for _, player := range g.state.Players {
+		for i := range g.state.Players {
+			player := &g.state.Players[i]
 			if player.Id != p.Id && player.DiveLevel == p.DiveLevel {
-				affectedPlayers++
-				fmt.Printf("\t[LOG] Distracting player '%s' at same level (%d)\n", player.Id, player.DiveLevel)
-				cards := player.Draw(2)
-				playerPanicCount := 0
+				cards := g.draw(player, 2, cause)
 				for _, card := range cards {
 					if card.GetType() == PanicType {
 						player.HandCards = append(player.HandCards, card)
-						playerPanicCount++
 					}
 				}
-				fmt.Printf("\t[LOG] Player '%s' received %d panic cards\n", player.Id, playerPanicCount)
-				value, found := player.ActiveEffects[CantExplore]
-				if !found {
-					player.ActiveEffects[CantExplore] = 1
-					fmt.Printf("\t[LOG] Player '%s' now has CantExplore effect (1 turn)\n", player.Id)
-				} else {
-					player.ActiveEffects[CantExplore] = value + 1
-					fmt.Printf("\t[LOG] Player '%s' CantExplore effect extended to %d turns\n", player.Id, value+1)
-				}
+				player.ActiveEffects[CantExplore]++
+				g.publish(PlayerDistracted{Player: p.Id, Target: player.Id, Turns: player.ActiveEffects[CantExplore]})
 			}
 		}
-		if affectedPlayers == 0 {
-			fmt.Printf("\t[LOG] No other players at same level to distract\n")
-		}
 
 	case UseObject:
 		itemToUse, hasItemParam := action.params[ItemToUse]
 		if !hasItemParam {
-			fmt.Printf("\t[LOG] UseObject action: Hold action (no item specified)\n")
-			return
-		}
-		itemIndex := itemToUse - 1 // Convert to 0-based index
-		if itemIndex < 0 || itemIndex >= len(p.Inventory) {
-			fmt.Printf("\t[LOG] UseObject action: invalid item index %d\n", itemToUse)
-			return
-		}
-		itemToActivate := p.Inventory[itemIndex]
-		if itemToActivate == nil {
-			fmt.Printf("\t[LOG] UseObject action: no item at inventory slot %d\n", itemToUse)
-			return
+			return nil
 		}
-		fmt.Printf("\t[LOG] UseObject action: using item '%s' (slot %d)\n", itemToActivate.name, itemToUse)
-		effectCount := 0
+		itemToActivate := p.Inventory[itemToUse-1]
+		g.publish(ItemUsed{Player: p.Id, Item: *itemToActivate, Slot: itemToUse})
+		consumed := false
 		for _, effect := range itemToActivate.effects {
-			effectCount++
-			fmt.Printf("\t[LOG] Applying effect: %s (value: %d)\n", effect.effectType, effect.value)
+			//Passive modifiers already apply while the item is held
+			if _, found := costModifiers[effect.effectType]; found {
+				consumed = chargeModifier(p, *itemToActivate, effect) || consumed
+				continue
+			}
 			switch effect.effectType {
 			case LookNextO2Cards:
-				fmt.Printf("%s NOT IMPLEMENTED", effect.effectType)
-			case MovementCostReduction:
-				fmt.Printf("%s NOT IMPLEMENTED", effect.effectType)
-			case BreathCostReduction:
-				fmt.Printf("%s NOT IMPLEMENTED", effect.effectType)
+				g.lookNextO2Cards(p, effect.value)
+				consumed = true
 			case BlockPlayer:
-				fmt.Printf("%s NOT IMPLEMENTED", effect.effectType)
-			case IgnorePanicActivation:
-				fmt.Printf("%s NOT IMPLEMENTED", effect.effectType)
-			case AnotherPlayerMustDrawO2:
-				fmt.Printf("%s NOT IMPLEMENTED", effect.effectType)
-			case StealItemFromPlayer:
-				fmt.Printf("%s NOT IMPLEMENTED", effect.effectType)
-			case StealAmuletFromPLayer:
-				fmt.Printf("%s NOT IMPLEMENTED", effect.effectType)
-			case RecoverDiscardedO2:
-				fmt.Printf("%s NOT IMPLEMENTED", effect.effectType)
-			case ReorderNextO2Cards:
-				fmt.Printf("%s NOT IMPLEMENTED", effect.effectType)
+				consumed = g.blockPlayer(p, action, *itemToActivate, effect.value) || consumed
+			case AnotherPlayerMustDrawO2,
+				StealItemFromPlayer,
+				StealAmuletFromPLayer,
+				RecoverDiscardedO2,
+				ReorderNextO2Cards:
+				g.publish(ItemEffectUnsupported{Player: p.Id, Effect: effect})
 			}
 		}
-		if effectCount == 0 {
-			fmt.Printf("\t[LOG] Item '%s' has no effects\n", itemToActivate.name)
+		if consumed {
+			g.dropItem(p, itemToUse-1, "USED")
+		}
+	}
+
+	return nil
+}
+
+// lookNextO2Cards shows the top cards of the deck to the player only, the
+// player keeps knowing them until they are drawn
+func (g *game) lookNextO2Cards(p *player, numberOfCards int) {
+	cards := p.OxygenCards[:min(numberOfCards, len(p.OxygenCards))]
+	p.KnownO2Cards = max(p.KnownO2Cards, len(cards))
+	g.publish(O2CardsLooked{Player: p.Id, Count: len(cards)})
+	if observer, ok := p.Controller.(O2CardObserver); ok {
+		observer.ObserveO2Cards(g.state, *p, slices.Clone(cards))
+	}
+}
+
+// blockTargets are the other divers alive on the level of the player or on
+// an adjacent one
+func blockTargets(gameState state, p player) []player {
+	targets := make([]player, 0)
+	for _, target := range gameState.Players {
+		if target.Id != p.Id && !target.IsDead() && target.DiveLevel >= p.DiveLevel-1 && target.DiveLevel <= p.DiveLevel+1 {
+			targets = append(targets, target)
+		}
+	}
+	return targets
+}
+
+// blockPlayer throws the net on the diver chosen by the player, the target
+// cannot move for its next turns. The net is lost only when it caught someone.
+func (g *game) blockPlayer(p *player, usedAction action, usedItem item, turns int) bool {
+
+	targets := blockTargets(g.state, *p)
+	if len(targets) == 0 {
+		return false
+	}
+
+	//An unknown target is asked again like an illegal action, after the last
+	//attempt the first diver in reach is caught
+	choice := 0
+	for range decisionAttempts {
+		decided := p.Controller.DecideTarget(g.state, *p, usedItem, targets)
+		if decided >= 0 && decided < len(targets) {
+			choice = decided
+			break
+		}
+		g.publish(ActionRejected{Player: p.Id, Action: usedAction, Err: &ActionError{
+			Player: p.Id,
+			Action: usedAction,
+			Err:    ErrUnknownTarget,
+			Reason: fmt.Sprintf("target %d is not one of the %d divers in reach", decided, len(targets)),
+		}})
+	}
+
+	for i := range g.state.Players {
+		target := &g.state.Players[i]
+		if target.Id == targets[choice].Id {
+			target.ActiveEffects[CantMove] = max(target.ActiveEffects[CantMove], turns)
+			g.publish(PlayerBlocked{Player: p.Id, Target: target.Id, Item: usedItem, Turns: target.ActiveEffects[CantMove]})
 		}
 	}
 
+	return true
 }
 
-func printCards(cards []card, message string) {
-	fmt.Println(message)
-	for _, card := range cards {
-		fmt.Printf("\t\t%s\n", card.GetName())
+// discardPanicCards makes the player discard the given number of panic
+// cards, or the whole hand when it holds fewer. The player picks them, when it
+// declines every card left the last one of the hand is discarded anyway.
+func (g *game) discardPanicCards(p *player, numberOfCards int) int {
+	discardedCard := 0
+	for discardedCard < numberOfCards && len(p.HandCards) > 0 {
+		chosen := -1
+		// Iterate backwards to safely remove elements
+		for i := len(p.HandCards) - 1; i >= 0; i-- {
+			if p.Controller.DecideDiscardPanicCard(g.state, *p, p.HandCards[i]) {
+				chosen = i
+				break
+			}
+		}
+		forced := chosen < 0
+		if forced {
+			chosen = len(p.HandCards) - 1
+		}
+
+		panicCard := p.HandCards[chosen]
+		discardedCard++
+		g.publish(PanicCardDiscarded{Player: p.Id, Card: panicCard, Forced: forced, Discarded: discardedCard, Required: min(numberOfCards, discardedCard+len(p.HandCards)-1)})
+		p.DiscardedCards = append(p.DiscardedCards, panicCard)
+		p.HandCards = append(p.HandCards[:chosen], p.HandCards[chosen+1:]...)
 	}
+	return discardedCard
 }
@@ -0,0 +1,143 @@
+package model
+
+import (
+	"errors"
+	"io"
+	"slices"
+	"testing"
+)
+
+func TestPanicCardsMustBeDiscarded(t *testing.T) {
+
+	g := newRandomGame(1)
+	p := &g.state.Players[0]
+	p.HandCards = nil
+	for _, card := range p.OxygenCards {
+		if card.GetType() == PanicType && len(p.HandCards) < 3 {
+			p.HandCards = append(p.HandCards, card)
+		}
+	}
+	p.Controller = NewScriptedController([]string{"N", "N", "N", "N", "N"})
+
+	discarded := g.discardPanicCards(p, 2)
+
+	if discarded != 2 || len(p.HandCards) != 1 {
+		t.Fatalf("declining every card discarded %d and kept %d, want 2 and 1", discarded, len(p.HandCards))
+	}
+}
+
+func TestRoundLimitEndsTheGame(t *testing.T) {
+
+	g := NewGame(
+		NewGameParameter(NumberOfPlayers, 2),
+		NewGameParameter(MaxRounds, 2),
+		NewGameParameter(Seed, 1),
+	)
+	g.SetOutput(io.Discard)
+	for i := range g.state.Players {
+		g.SetController(i, NewRandomController(g.NewRandomizer()))
+	}
+
+	result := g.Run(1)[0]
+
+	if result.EndReason != RoundLimitReached || result.Rounds != 2 {
+		t.Fatalf("game ended with %s after %d rounds, want %s after 2", result.EndReason, result.Rounds, RoundLimitReached)
+	}
+}
+
+// targetController answers the net targets in order, the rest is left to
+// the random bot
+type targetController struct {
+	PlayerController
+	targets []int
+}
+
+func (c *targetController) DecideTarget(gameState state, p player, usedItem item, targets []player) int {
+	target := c.targets[0]
+	c.targets = c.targets[1:]
+	return target
+}
+
+func TestNetAsksAgainForAnUnknownTarget(t *testing.T) {
+
+	g := newRandomGame(1)
+	for i := range g.state.Players {
+		g.state.Players[i].DiveLevel = 5
+	}
+	p := &g.state.Players[0]
+	p.Controller = &targetController{PlayerController: p.Controller, targets: []int{5, 1}}
+
+	var rejected []error
+	g.Subscribe(func(event Event) {
+		if e, ok := event.(ActionRejected); ok {
+			rejected = append(rejected, e.Err)
+		}
+	})
+
+	useNet := NewAction(UseObject, map[actionParam]int{ItemToUse: 1})
+	if !g.blockPlayer(p, useNet, net, net.effects[0].value) {
+		t.Fatalf("the net caught nobody")
+	}
+
+	if len(rejected) != 1 || !errors.Is(rejected[0], ErrUnknownTarget) {
+		t.Fatalf("rejections = %v, want one %v", rejected, ErrUnknownTarget)
+	}
+	if _, found := g.state.Players[1].ActiveEffects[CantMove]; found {
+		t.Errorf("the first diver in reach was caught instead of the second one")
+	}
+	if _, found := g.state.Players[2].ActiveEffects[CantMove]; !found {
+		t.Errorf("the second diver in reach was not caught")
+	}
+}
+
+func TestReinforcedNetBlocksTwoTurns(t *testing.T) {
+
+	g := newRandomGame(1)
+	for i := range g.state.Players {
+		g.state.Players[i].DiveLevel = 5
+	}
+	p := &g.state.Players[0]
+	p.Controller = &targetController{PlayerController: p.Controller, targets: []int{0}}
+
+	useNet := NewAction(UseObject, map[actionParam]int{ItemToUse: 1})
+	g.blockPlayer(p, useNet, reinforcedNet, reinforcedNet.effects[0].value)
+
+	target := &g.state.Players[1]
+	for turn := 1; turn <= 3; turn++ {
+		actions := target.CheckPlayerEffects()
+		blocked := !slices.Contains(actions, Ascend) && !slices.Contains(actions, Dive)
+		if blocked != (turn <= 2) {
+			t.Errorf("turn %d: actions %v, blocked = %t", turn, actions, blocked)
+		}
+	}
+}
+
+func TestCancelledPanicDiscardsItsCards(t *testing.T) {
+
+	g := newRandomGame(1)
+	p := &g.state.Players[0]
+	p.Controller = NewHeuristicController(DefaultHeuristicWeights())
+	kit := antistressKit
+	p.Inventory[0] = &kit
+	p.HandCards = nil
+	for range g.state.PanicThreshold {
+		p.HandCards = append(p.HandCards, panicCard{panicTypes: []panicType{Blue}})
+	}
+	discarded := len(p.DiscardedCards)
+
+	activated := 0
+	g.Subscribe(func(event Event) {
+		if _, ok := event.(PanicActivated); ok {
+			activated++
+		}
+	})
+
+	g.checkPanic(p)
+
+	if activated != 0 || p.Inventory[0] != nil {
+		t.Fatalf("%d panics activated, kit left = %t, want the kit spent on the panic", activated, p.Inventory[0] != nil)
+	}
+	if len(p.HandCards) != 0 || len(p.DiscardedCards)-discarded != g.state.PanicThreshold {
+		t.Fatalf("%d cards kept and %d discarded, want the %d cards of the panic discarded", len(p.HandCards), len(p.DiscardedCards)-discarded, g.state.PanicThreshold)
+	}
+}
@@ -0,0 +1,314 @@
+package model
+
+import (
+	"encoding/json"
+	"math"
+	"os"
+)
+
+type HeuristicWeights struct {
+	Oxygen          float64 `json:"oxygen"`
+	AmuletZone      float64 `json:"amuletZone"`
+	Panic           float64 `json:"panic"`
+	Amulets         float64 `json:"amulets"`
+	AmuletsAtBottom float64 `json:"amuletsAtBottom"`
+	Treasure        float64 `json:"treasure"`
+	Utility         float64 `json:"utility"`
+	Distract        float64 `json:"distract"`
+	Waiting         float64 `json:"waiting"`
+}
+
+func DefaultHeuristicWeights() HeuristicWeights {
+	return HeuristicWeights{
+		Oxygen:          0.3,
+		AmuletZone:      6,
+		Panic:           -4,
+		Amulets:         20,
+		AmuletsAtBottom: 60,
+		Treasure:        1.5,
+		Utility:         1,
+		Distract:        0.5,
+		Waiting:         -3,
+	}
+}
+
+func LoadHeuristicWeights(path string) (HeuristicWeights, error) {
+	weights := DefaultHeuristicWeights()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return weights, err
+	}
+
+	err = json.Unmarshal(data, &weights)
+
+	return weights, err
+}
+
+func (w HeuristicWeights) Save(path string) error {
+	data, err := json.MarshalIndent(w, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0644)
+}
+
+type heuristicController struct {
+	weights     HeuristicWeights
+	explanation []RankedAction
+}
+
+func NewHeuristicController(weights HeuristicWeights) *heuristicController {
+	return &heuristicController{
+		weights: weights,
+	}
+}
+
+func (h *heuristicController) clone() PlayerController {
+	return NewHeuristicController(h.weights)
+}
+
+func (h *heuristicController) DecideAction(gameState state, p player, availableActions []actionType) action {
+
+	var bestAction action
+	bestScore := math.Inf(-1)
+	h.explanation = make([]RankedAction, 0)
+
+	for _, candidate := range legalActions(gameState, p, availableActions) {
+		score := h.Evaluate(forecast(gameState, p, candidate))
+		if score > bestScore {
+			bestScore = score
+			bestAction = candidate
+		}
+		h.explanation = append(h.explanation, RankedAction{
+			Action:    candidate,
+			Score:     score,
+			Rationale: rationale(gameState, p, candidate),
+		})
+	}
+
+	rankActions(h.explanation)
+
+	return bestAction
+}
+
+func (h *heuristicController) Explain() []RankedAction {
+	return h.explanation
+}
+
+func (h *heuristicController) DecideReplaceItem(gameState state, p player, newItem item, slot int) bool {
+	return h.itemValue(newItem) > h.itemValue(*p.Inventory[slot])
+}
+
+func (h *heuristicController) DecideDiscardPanicCard(gameState state, p player, panicCard card) bool {
+	return true
+}
+
+// The net goes to the diver with the best score, the deepest one on a tie
+func (h *heuristicController) DecideTarget(gameState state, p player, usedItem item, targets []player) int {
+	best := 0
+	for i, target := range targets[1:] {
+		score, bestScore := target.Score(), targets[best].Score()
+		if score > bestScore || score == bestScore && target.DiveLevel > targets[best].DiveLevel {
+			best = i + 1
+		}
+	}
+	return best
+}
+
+// Every activation hurts, the kit is spent on the first one
+func (h *heuristicController) DecideCancelPanic(gameState state, p player, usedItem item, panicType panicType) bool {
+	return true
+}
+
+func (h *heuristicController) itemValue(i item) float64 {
+	switch i.itemType {
+	case Amulets:
+		return h.weights.Amulets * float64(i.quantity)
+	case TreasureToken:
+		return h.weights.Treasure * float64(i.quantity)
+	}
+	return h.weights.Utility
+}
+
+func (h *heuristicController) Evaluate(f outlook) float64 {
+
+	score := h.weights.Oxygen * (f.oxygen - float64(f.breathCost))
+
+	switch {
+	case f.level < 7:
+		score -= h.weights.AmuletZone * float64(7-f.level)
+	case f.level > 9:
+		score -= h.weights.AmuletZone * float64(f.level-9)
+	}
+
+	panicRisk := 0.0
+	for _, panicType := range panicTypes {
+		ratio := f.panics[panicType] / f.threshold
+		panicRisk += ratio * ratio
+	}
+	score += h.weights.Panic * panicRisk
+
+	score += h.weights.Amulets * f.amulets
+	if f.level == 10 {
+		score += h.weights.AmuletsAtBottom * f.amulets
+	}
+	score += h.weights.Treasure * f.treasure
+	score += h.weights.Utility * f.utility
+	score += h.weights.Distract * float64(f.distracted)
+	//Breathing is free at the bottom, a diver waiting there would never die
+	if f.waited {
+		score += h.weights.Waiting
+	}
+
+	return score
+}
+
+type outlook struct {
+	oxygen     float64
+	level      int
+	handCards  float64
+	panics     map[panicType]float64
+	amulets    float64
+	treasure   float64
+	utility    float64
+	distracted int
+	breathCost int
+	//threshold is the number of cards activating a panic
+	threshold float64
+	waited    bool
+}
+
+func forecast(gameState state, p player, actionToDo action) outlook {
+
+	f := outlook{
+		oxygen:    float64(len(p.OxygenCards)),
+		level:     p.DiveLevel,
+		handCards: float64(len(p.HandCards)),
+		panics:    make(map[panicType]float64),
+		threshold: float64(gameState.PanicThreshold),
+	}
+
+	for _, card := range p.HandCards {
+		for _, panicType := range card.(panicCard).panicTypes {
+			f.panics[panicType]++
+		}
+	}
+
+	for _, item := range p.Inventory {
+		if item == nil {
+			continue
+		}
+		switch item.itemType {
+		case Amulets:
+			f.amulets += float64(item.quantity)
+		case TreasureToken:
+			f.treasure += float64(item.quantity)
+		default:
+			f.utility++
+		}
+	}
+
+	//Expected values come from the composition of the deck, the order is only
+	//used for the cards the player has seen
+	draw := func(numberOfCards int) []float64 {
+		chances := drawChances(p, numberOfCards)
+		for i, card := range p.OxygenCards {
+			if card.GetType() == PanicType {
+				f.handCards += chances[i]
+				for _, panicType := range card.(panicCard).panicTypes {
+					f.panics[panicType] += chances[i]
+				}
+			}
+		}
+		f.oxygen -= min(float64(numberOfCards), f.oxygen)
+		return chances
+	}
+
+	discard := func(numberOfCards int) {
+		if f.handCards <= 0 {
+			return
+		}
+		kept := max(0, 1-float64(numberOfCards)/f.handCards)
+		f.handCards *= kept
+		for panicType := range f.panics {
+			f.panics[panicType] *= kept
+		}
+	}
+
+	switch actionToDo.actionType {
+	case Dive:
+		f.level = min(10, f.level+actionToDo.params[DiveLevels])
+		cost, _ := modifiedCost(p, MovementCost, actionToDo.params[DiveLevels])
+		draw(cost)
+	case Ascend:
+		f.level = max(1, f.level-actionToDo.params[AscendLevels])
+		cost, _ := modifiedCost(p, MovementCost, actionToDo.params[AscendLevels])
+		draw(cost)
+		discard(actionToDo.params[AscendLevels] + 1)
+	case CalmDown:
+		draw(1)
+		discard(3)
+	case Distract:
+		draw(2)
+		for _, player := range gameState.Players {
+			if player.Id != p.Id && player.DiveLevel == p.DiveLevel && !player.IsDead() {
+				f.distracted++
+			}
+		}
+	case UseObject:
+		//Holding and using an item that is always on leave the diver as it is
+		f.waited = true
+		if slot, found := actionToDo.params[ItemToUse]; found {
+			for _, effect := range p.Inventory[slot-1].effects {
+				if modifier, ok := costModifiers[effect.effectType]; !ok || !modifier.passive {
+					f.waited = false
+				}
+			}
+		}
+	case Explore:
+		chances := draw(actionToDo.params[ExploreTime])
+		for i, card := range p.OxygenCards {
+			if card.GetType() != ItemType {
+				continue
+			}
+			found, ok := card.(itemCard).items[p.DiveLevel]
+			if !ok {
+				continue
+			}
+			switch found.itemType {
+			case Amulets:
+				f.amulets += chances[i] * float64(found.quantity)
+			case TreasureToken:
+				f.treasure += chances[i] * float64(found.quantity)
+			default:
+				f.utility += chances[i]
+			}
+		}
+	}
+
+	f.breathCost, _ = modifiedCost(p, BreathingCost, BreathCost(f.level))
+
+	return f
+}
+
+// drawChances gives the chance of every card of the deck to be among the
+// next cards drawn, the cards the player knows are drawn first
+func drawChances(p player, numberOfCards int) []float64 {
+
+	chances := make([]float64, len(p.OxygenCards))
+	known := min(p.KnownO2Cards, len(p.OxygenCards))
+	numberOfCards = min(numberOfCards, len(p.OxygenCards))
+
+	for i := range chances {
+		switch {
+		case i < known && i < numberOfCards:
+			chances[i] = 1
+		case i >= known:
+			chances[i] = float64(max(0, numberOfCards-known)) / float64(len(p.OxygenCards)-known)
+		}
+	}
+
+	return chances
+}
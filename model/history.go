@@ -0,0 +1,170 @@
+package model
+
+import "errors"
+
+// A controller can return these requests instead of an action, the engine
+// answers them once the decision phase is over and asks for a decision again
+const (
+	UndoRequest actionType = "UNDO"
+	RedoRequest actionType = "REDO"
+)
+
+var (
+	ErrUndoDisabled  = errors.New("undo is not enabled")
+	ErrNothingToUndo = errors.New("nothing to undo")
+	ErrNothingToRedo = errors.New("nothing to redo")
+	ErrCardsRevealed = errors.New("cards were revealed since the decision")
+)
+
+// snapshot is the game right before a decision, drawn counts the cards
+// revealed since the start of the game at that time
+type snapshot struct {
+	state  state
+	source randomSource
+	turn   turn
+	drawn  int
+}
+
+// history keeps a snapshot of every decision played, in a sandbox the
+// decisions can be undone even after cards were drawn
+type history struct {
+	sandbox bool
+	drawn   int
+	undo    []snapshot
+	redo    []snapshot
+}
+
+// EnableUndo starts recording the decisions of the game so that they can be
+// undone and redone. Outside a sandbox a decision can only be undone until a
+// card is drawn, which leaves the time between the decision and its resolution.
+func (g *game) EnableUndo(sandbox bool) {
+
+	if g.history != nil {
+		g.history.sandbox = sandbox
+		return
+	}
+
+	g.history = &history{sandbox: sandbox}
+
+	g.BeforePhase(func(phase Phase, playerIndex int) {
+		if phase == DecisionPhase {
+			g.history.undo = append(g.history.undo, g.snapshot())
+		}
+	})
+
+	//Redo is left until a decision is played
+	g.AfterPhase(func(phase Phase, playerIndex int) {
+		if phase == DecisionPhase && g.turn.action != nil {
+			g.history.redo = nil
+		}
+	})
+
+	g.Subscribe(func(event Event) {
+		switch e := event.(type) {
+		case Breathed:
+			g.history.drawn += len(e.Cards)
+		case CardsDrawn:
+			g.history.drawn += len(e.Cards)
+		case O2CardsLooked:
+			g.history.drawn += e.Count
+		}
+	})
+}
+
+func (g *game) snapshot() snapshot {
+	return snapshot{
+		state:  g.state.Clone(),
+		source: *g.source,
+		turn:   g.turn.clone(),
+		drawn:  g.history.drawn,
+	}
+}
+
+func (g *game) restore(s snapshot) {
+	g.state = s.state.Clone()
+	*g.source = s.source
+	g.turn = s.turn.clone()
+}
+
+// CanUndo tells whether Undo would succeed and why not
+func (g *game) CanUndo() error {
+	if g.history == nil {
+		return ErrUndoDisabled
+	}
+	if len(g.history.undo) == 0 {
+		return ErrNothingToUndo
+	}
+	last := g.history.undo[len(g.history.undo)-1]
+	if !g.history.sandbox && last.drawn != g.history.drawn {
+		return ErrCardsRevealed
+	}
+	return nil
+}
+
+// Undo goes back to the last decision played, the player is asked again
+// by the next Step
+func (g *game) Undo() error {
+
+	err := g.CanUndo()
+	if err != nil {
+		return err
+	}
+
+	last := g.history.undo[len(g.history.undo)-1]
+	g.history.undo = g.history.undo[:len(g.history.undo)-1]
+	g.history.redo = append(g.history.redo, g.snapshot())
+	g.restore(last)
+	//A submitted action was part of the decision that is undone
+	g.turn.action = nil
+
+	return nil
+}
+
+// Redo goes forward to where the last Undo started from, as long as no
+// decision was played since
+func (g *game) Redo() error {
+
+	if g.history == nil {
+		return ErrUndoDisabled
+	}
+	if len(g.history.redo) == 0 {
+		return ErrNothingToRedo
+	}
+
+	next := g.history.redo[len(g.history.redo)-1]
+	g.history.redo = g.history.redo[:len(g.history.redo)-1]
+	g.history.undo = append(g.history.undo, g.snapshot())
+	g.restore(next)
+
+	return nil
+}
+
+func isHistoryRequest(a action) bool {
+	return a.actionType == UndoRequest || a.actionType == RedoRequest
+}
+
+// answerRequest undoes or redoes for the player who asked while deciding,
+// up to the next decision of that player so that the other controllers do
+// not play again right away. The snapshot of the decision left unplayed is
+// dropped first.
+func (g *game) answerRequest(player string, request actionType) {
+
+	if g.history != nil && len(g.history.undo) > 0 {
+		g.history.undo = g.history.undo[:len(g.history.undo)-1]
+	}
+
+	move := g.Redo
+	if request == UndoRequest {
+		move = g.Undo
+	}
+
+	err := move()
+	if err != nil {
+		g.publish(HistoryRejected{Player: player, Request: request, Err: err})
+		return
+	}
+	for g.GetActualPlayer().Id != player && move() == nil {
+	}
+
+	g.publish(HistoryChanged{Player: player, Request: request, Round: g.state.Round, Next: g.GetActualPlayer().Id})
+}
@@ -0,0 +1,78 @@
+package model
+
+import (
+	"io"
+	"slices"
+	"testing"
+)
+
+func newRandomGame(seed int) game {
+	g := NewGame(
+		NewGameParameter(NumberOfPlayers, 3),
+		NewGameParameter(NumberOfItemSlots, 3),
+		NewGameParameter(Seed, seed),
+	)
+	g.SetOutput(io.Discard)
+	for i := range g.state.Players {
+		g.SetController(i, NewRandomController(g.NewRandomizer()))
+	}
+	return g
+}
+
+func TestRedoKeepsTheChosenAction(t *testing.T) {
+
+	g := newRandomGame(1)
+	g.EnableUndo(false)
+
+	for g.Phase() != ResolutionPhase {
+		g.Step()
+	}
+	chosen := actionKey(*g.turn.action)
+
+	if err := g.Undo(); err != nil {
+		t.Fatalf("Undo() = %v", err)
+	}
+	if g.Phase() != DecisionPhase || g.turn.action != nil {
+		t.Fatalf("after Undo() phase = %s, action = %v, want %s without action", g.Phase(), g.turn.action, DecisionPhase)
+	}
+
+	if err := g.Redo(); err != nil {
+		t.Fatalf("Redo() = %v", err)
+	}
+	if g.Phase() != ResolutionPhase || g.turn.action == nil || actionKey(*g.turn.action) != chosen {
+		t.Fatalf("after Redo() phase = %s, action = %v, want %s with '%s'", g.Phase(), g.turn.action, ResolutionPhase, chosen)
+	}
+
+	if next := g.Step(); next != AftermathPhase {
+		t.Fatalf("Step() after Redo() = %s, want %s", next, AftermathPhase)
+	}
+}
+
+func TestUndoIsRefusedAfterLookingAtCards(t *testing.T) {
+
+	g := newRandomGame(1)
+	g.EnableUndo(false)
+
+	for g.Phase() != DecisionPhase {
+		g.Step()
+	}
+	p := &g.state.Players[g.state.ActualPlayer]
+	light := flashlight
+	p.Inventory[0] = &light
+	if !slices.Contains(g.turn.availableActions, UseObject) {
+		g.turn.availableActions = append(g.turn.availableActions, UseObject)
+	}
+	useLight := NewAction(UseObject, map[actionParam]int{ItemToUse: 1})
+	g.turn.action = &useLight
+
+	for g.Phase() != AftermathPhase {
+		g.Step()
+	}
+	if p.KnownO2Cards == 0 {
+		t.Fatalf("the flashlight was not used")
+	}
+
+	if err := g.Undo(); err != ErrCardsRevealed {
+		t.Fatalf("Undo() after the flashlight = %v, want %v", err, ErrCardsRevealed)
+	}
+}
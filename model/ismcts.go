@@ -0,0 +1,91 @@
+package model
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"strings"
+)
+
+// observation is what a player may legally know about the game: its own
+// hand, the public inventories, levels and deck sizes and the top cards of
+// its own deck revealed by items. Unseen cards are kept in a canonical order
+// so that nothing about their real position leaks into the search.
+type observation struct {
+	state     state
+	observer  int
+	unseen    [][]card
+	handSizes []int
+}
+
+func NewInformationSetController(config MCTSConfig, randomizer *rand.Rand) *mctsController {
+	return newSearchController(config, randomizer, observationSamples)
+}
+
+func observe(gameState state, observer int) observation {
+
+	o := observation{
+		state:     gameState.Clone(),
+		observer:  observer,
+		unseen:    make([][]card, len(gameState.Players)),
+		handSizes: make([]int, len(gameState.Players)),
+	}
+
+	for i := range o.state.Players {
+		p := &o.state.Players[i]
+		if i == observer {
+			known := min(p.KnownO2Cards, len(p.OxygenCards))
+			o.unseen[i] = canonicalOrder(p.OxygenCards[known:])
+			p.OxygenCards = p.OxygenCards[:known]
+		} else {
+			o.handSizes[i] = len(p.HandCards)
+			o.unseen[i] = canonicalOrder(append(p.OxygenCards, p.HandCards...))
+			p.OxygenCards = make([]card, 0)
+			p.HandCards = make([]card, 0)
+			p.KnownO2Cards = 0
+		}
+	}
+
+	return o
+}
+
+func canonicalOrder(cards []card) []card {
+	sorted := slices.Clone(cards)
+	slices.SortStableFunc(sorted, func(a, b card) int {
+		return strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+	})
+	return sorted
+}
+
+// sample deals the unseen cards into one of the states consistent with the
+// observation
+func (o observation) sample(randomizer *rand.Rand) state {
+
+	sample := o.state.Clone()
+
+	for i := range sample.Players {
+		p := &sample.Players[i]
+		pool := slices.Clone(o.unseen[i])
+		shuffleCards(randomizer, pool)
+
+		if i == o.observer {
+			p.OxygenCards = append(p.OxygenCards, pool...)
+			continue
+		}
+
+		for _, card := range pool {
+			if len(p.HandCards) < o.handSizes[i] && card.GetType() == PanicType {
+				p.HandCards = append(p.HandCards, card)
+			} else {
+				p.OxygenCards = append(p.OxygenCards, card)
+			}
+		}
+	}
+
+	return sample
+}
+
+func observationSamples(gameState state, observer int) func(randomizer *rand.Rand) state {
+	o := observe(gameState, observer)
+	return o.sample
+}
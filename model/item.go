@@ -1,5 +1,7 @@
 package model
 
+import "slices"
+
 type itemCardRarity string
 
 const (
@@ -15,6 +17,15 @@ type itemCard struct {
 	items  map[int]item
 }
 
+func (c itemCard) clone() itemCard {
+	items := make(map[int]item, len(c.items))
+	for level, item := range c.items {
+		items[level] = item.clone()
+	}
+	c.items = items
+	return c
+}
+
 type itemEffectType string
 
 const (
@@ -50,6 +61,17 @@ type item struct {
 	quantity int
 }
 
+func (i item) clone() item {
+	i.effects = slices.Clone(i.effects)
+	return i
+}
+
+func (i item) hasEffect(effectType itemEffectType) bool {
+	return slices.ContainsFunc(i.effects, func(effect itemEffect) bool {
+		return effect.effectType == effectType
+	})
+}
+
 var flashlight = item{
 	name:     "flashlight",
 	itemType: Utility,
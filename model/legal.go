@@ -0,0 +1,210 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// LegalActions returns every action the player may take right now. The
+// actual player is bound to the action types left at the start of the turn,
+// the others to the ones their active effects would leave them.
+func (g *game) LegalActions(playerIndex int) []action {
+
+	p := g.state.Players[playerIndex]
+
+	availableActions := p.allowedActionTypes()
+	if playerIndex == g.state.ActualPlayer && g.turn.phase == DecisionPhase {
+		availableActions = g.turn.availableActions
+	}
+
+	return legalActions(g.state, p, availableActions)
+}
+
+// legalActions gives the parameters of every available action type, holding
+// comes with the objects and is left when nothing else is allowed
+func legalActions(gameState state, p player, availableActions []actionType) []action {
+	actions := make([]action, 0)
+
+	for _, actionType := range availableActions {
+		actions = appendLegalActions(actions, gameState, p, actionType)
+	}
+
+	if len(actions) == 0 {
+		actions = append(actions, NewAction(UseObject, map[actionParam]int{}))
+	}
+
+	return actions
+}
+
+// appendLegalActions adds the legal parameters of one action type
+func appendLegalActions(actions []action, gameState state, p player, actionType actionType) []action {
+
+	switch actionType {
+	case Dive:
+		for levels := 1; levels <= 3 && p.DiveLevel+levels <= 10; levels++ {
+			actions = append(actions, NewAction(Dive, map[actionParam]int{DiveLevels: levels}))
+		}
+	case Ascend:
+		for levels := 1; levels <= 3 && p.DiveLevel-levels >= 1; levels++ {
+			actions = append(actions, NewAction(Ascend, map[actionParam]int{AscendLevels: levels}))
+		}
+	case Explore:
+		for time := 1; time <= 3 && time <= len(p.OxygenCards); time++ {
+			actions = append(actions, NewAction(Explore, map[actionParam]int{ExploreTime: time}))
+		}
+	case UseObject:
+		targets := len(blockTargets(gameState, p)) > 0
+		for i, item := range p.Inventory {
+			if item != nil && (targets || !item.hasEffect(BlockPlayer)) && !item.hasEffect(IgnorePanicActivation) {
+				actions = append(actions, NewAction(UseObject, map[actionParam]int{ItemToUse: i + 1}))
+			}
+		}
+		actions = append(actions, NewAction(UseObject, map[actionParam]int{}))
+	case Distract:
+		for _, player := range gameState.Players {
+			if player.Id != p.Id && player.DiveLevel == p.DiveLevel && !player.IsDead() {
+				actions = append(actions, NewAction(Distract, map[actionParam]int{}))
+				break
+			}
+		}
+	default:
+		actions = append(actions, NewAction(actionType, map[actionParam]int{}))
+	}
+
+	return actions
+}
+
+var (
+	ErrUnknownAction      = errors.New("unknown action")
+	ErrInvalidArgument    = errors.New("invalid action argument")
+	ErrActionNotAvailable = errors.New("action not available")
+	ErrLevelOutOfRange    = errors.New("level out of range")
+	ErrNotEnoughOxygen    = errors.New("not enough oxygen")
+	ErrEmptySlot          = errors.New("empty inventory slot")
+	ErrNoTarget           = errors.New("no target diver")
+	ErrUnknownTarget      = errors.New("unknown target diver")
+)
+
+// ActionError tells why an action was rejected, errors.Is matches it with
+// the error of the broken rule
+type ActionError struct {
+	Player string
+	Action action
+	Err    error
+	Reason string
+}
+
+func (e *ActionError) Error() string {
+	return fmt.Sprintf("player '%s' cannot %s: %s", e.Player, e.Action.actionType, e.Reason)
+}
+
+func (e *ActionError) Unwrap() error {
+	return e.Err
+}
+
+func (g *game) ValidateAction(playerIndex int, actionToDo action) error {
+
+	p := g.state.Players[playerIndex]
+
+	availableActions := p.allowedActionTypes()
+	if playerIndex == g.state.ActualPlayer && g.turn.phase == DecisionPhase {
+		availableActions = g.turn.availableActions
+	}
+
+	return validateAction(g.state, p, availableActions, actionToDo)
+}
+
+// validateAction accepts the actions listed by legalActions, the rules are
+// only looked at again to tell why an action is rejected. Only the actions
+// of the same type are listed, it runs for every action of the playouts.
+func validateAction(gameState state, p player, availableActions []actionType, actionToDo action) error {
+
+	var candidates []action
+	if slices.Contains(availableActions, actionToDo.actionType) {
+		candidates = appendLegalActions(candidates, gameState, p, actionToDo.actionType)
+	} else {
+		//Holding is left when nothing else is allowed
+		candidates = legalActions(gameState, p, availableActions)
+	}
+	for _, candidate := range candidates {
+		if candidate.sameAs(actionToDo) {
+			return nil
+		}
+	}
+
+	reject := func(err error, format string, a ...any) error {
+		return &ActionError{
+			Player: p.Id,
+			Action: actionToDo,
+			Err:    err,
+			Reason: fmt.Sprintf(format, a...),
+		}
+	}
+
+	argument := func(param actionParam, minValue int, maxValue int) (int, error) {
+		name := strings.ToLower(strings.ReplaceAll(string(param), "_", " "))
+		value, found := actionToDo.params[param]
+		if !found {
+			return 0, reject(ErrInvalidArgument, "%s is missing", name)
+		}
+		if value < minValue || value > maxValue {
+			return 0, reject(ErrInvalidArgument, "%s must be between %d and %d", name, minValue, maxValue)
+		}
+		return value, nil
+	}
+
+	switch actionToDo.actionType {
+	case Dive, Ascend, Explore, CalmDown, Distract, UseObject:
+	default:
+		return reject(ErrUnknownAction, "%s is not an action", actionToDo.actionType)
+	}
+	if !slices.Contains(availableActions, actionToDo.actionType) {
+		return reject(ErrActionNotAvailable, "only %v are available", availableActions)
+	}
+
+	switch actionToDo.actionType {
+	case Dive:
+		levels, err := argument(DiveLevels, 1, 3)
+		if err != nil {
+			return err
+		}
+		if p.DiveLevel+levels > 10 {
+			return reject(ErrLevelOutOfRange, "level %d is below level 10", p.DiveLevel+levels)
+		}
+	case Ascend:
+		levels, err := argument(AscendLevels, 1, 3)
+		if err != nil {
+			return err
+		}
+		if p.DiveLevel-levels < 1 {
+			return reject(ErrLevelOutOfRange, "level %d is above level 1", p.DiveLevel-levels)
+		}
+	case Explore:
+		time, err := argument(ExploreTime, 1, 3)
+		if err != nil {
+			return err
+		}
+		if time > len(p.OxygenCards) {
+			return reject(ErrNotEnoughOxygen, "only %d oxygen cards are left", len(p.OxygenCards))
+		}
+	case UseObject:
+		slot, err := argument(ItemToUse, 1, len(p.Inventory))
+		if err != nil {
+			return err
+		}
+		switch usedItem := p.Inventory[slot-1]; {
+		case usedItem == nil:
+			return reject(ErrEmptySlot, "slot %d is empty", slot)
+		case usedItem.hasEffect(IgnorePanicActivation):
+			return reject(ErrActionNotAvailable, "'%s' is only used when a panic activates", usedItem.name)
+		case usedItem.hasEffect(BlockPlayer):
+			return reject(ErrNoTarget, "nobody is within one level of level %d", p.DiveLevel)
+		}
+	case Distract:
+		return reject(ErrNoTarget, "nobody else is at level %d", p.DiveLevel)
+	}
+
+	return reject(ErrInvalidArgument, "unexpected arguments %v", actionToDo.params)
+}
@@ -0,0 +1,42 @@
+package model
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestHoldIsTheOnlyFallback(t *testing.T) {
+
+	hold := NewAction(UseObject, map[actionParam]int{})
+
+	tests := []struct {
+		effect playerEffect
+		legal  bool
+	}{
+		{effect: HaveToCalmDown, legal: false},
+		{effect: SkipTurn, legal: true},
+	}
+
+	for _, test := range tests {
+		g := newRandomGame(1)
+		p := &g.state.Players[0]
+		p.ActiveEffects[test.effect] = 1
+		availableActions := p.allowedActionTypes()
+
+		legal := false
+		for _, candidate := range legalActions(g.state, *p, availableActions) {
+			legal = legal || actionKey(candidate) == actionKey(hold)
+		}
+		err := validateAction(g.state, *p, availableActions, hold)
+
+		if legal != test.legal {
+			t.Errorf("with %s hold is legal = %v, want %v", test.effect, legal, test.legal)
+		}
+		if (err == nil) != test.legal {
+			t.Errorf("with %s validating hold = %v, want legal = %v", test.effect, err, test.legal)
+		}
+		if err != nil && !errors.Is(err, ErrActionNotAvailable) {
+			t.Errorf("with %s validating hold = %v, want %v", test.effect, err, ErrActionNotAvailable)
+		}
+	}
+}
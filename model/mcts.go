@@ -0,0 +1,303 @@
+package model
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"math/rand"
+	"slices"
+	"time"
+)
+
+type MCTSConfig struct {
+	Iterations  int
+	TimeBudget  time.Duration
+	Exploration float64
+}
+
+func DefaultMCTSConfig() MCTSConfig {
+	return MCTSConfig{
+		Iterations:  500,
+		Exploration: math.Sqrt2,
+	}
+}
+
+// A sampler returns a function drawing the states the search plays on
+type sampler func(gameState state, observer int) func(randomizer *rand.Rand) state
+
+type mctsController struct {
+	config      MCTSConfig
+	source      *randomSource
+	randomizer  *rand.Rand
+	sampler     sampler
+	fallback    *heuristicController
+	explanation []RankedAction
+}
+
+// The search is deterministic for a given randomizer as long as only the
+// iteration budget is set, a time budget depends on the speed of the machine
+func NewMCTSController(config MCTSConfig, randomizer *rand.Rand) *mctsController {
+	return newSearchController(config, randomizer, shuffledDecks)
+}
+
+func NewOmniscientController(config MCTSConfig, randomizer *rand.Rand) *mctsController {
+	return newSearchController(config, randomizer, trueState)
+}
+
+// The clone searches with a copy of the source, it finds the same actions
+// as the original on the same states
+func (m *mctsController) clone() PlayerController {
+	source := *m.source
+	return &mctsController{
+		config:     m.config,
+		source:     &source,
+		randomizer: rand.New(&source),
+		sampler:    m.sampler,
+		fallback:   NewHeuristicController(m.fallback.weights),
+	}
+}
+
+// The search draws from its own source seeded by the randomizer
+func newSearchController(config MCTSConfig, randomizer *rand.Rand, sampler sampler) *mctsController {
+	source := newRandomSource(randomizer.Int63())
+	return &mctsController{
+		config:     config,
+		source:     source,
+		randomizer: rand.New(source),
+		sampler:    sampler,
+		fallback:   NewHeuristicController(DefaultHeuristicWeights()),
+	}
+}
+
+// Only the order of the oxygen decks is unknown, everything else in the
+// state is taken as it is, as well as the top cards the observer has seen
+func shuffledDecks(gameState state, observer int) func(randomizer *rand.Rand) state {
+	return func(randomizer *rand.Rand) state {
+		sample := gameState.Clone()
+		for i := range sample.Players {
+			p := &sample.Players[i]
+			known := 0
+			if i == observer {
+				known = min(p.KnownO2Cards, len(p.OxygenCards))
+			}
+			shuffleCards(randomizer, p.OxygenCards[known:])
+		}
+		return sample
+	}
+}
+
+func trueState(gameState state, observer int) func(randomizer *rand.Rand) state {
+	return func(randomizer *rand.Rand) state {
+		return gameState.Clone()
+	}
+}
+
+func shuffleCards(randomizer *rand.Rand, cards []card) {
+	randomizer.Shuffle(len(cards), func(i, j int) {
+		cards[i], cards[j] = cards[j], cards[i]
+	})
+}
+
+type mctsNode struct {
+	action   action
+	visits   int
+	reward   float64
+	children map[string]*mctsNode
+}
+
+func newMCTSNode(action action) *mctsNode {
+	return &mctsNode{
+		action:   action,
+		children: make(map[string]*mctsNode),
+	}
+}
+
+func (m *mctsController) DecideAction(gameState state, p player, availableActions []actionType) action {
+
+	root := m.search(gameState, p, availableActions)
+
+	var bestAction action
+	bestVisits := -1
+	m.explanation = make([]RankedAction, 0)
+	for _, candidate := range legalActions(gameState, p, availableActions) {
+		child, found := root.children[actionKey(candidate)]
+		if !found {
+			continue
+		}
+		if child.visits > bestVisits {
+			bestVisits = child.visits
+			bestAction = child.action
+		}
+		m.explanation = append(m.explanation, RankedAction{
+			Action:    candidate,
+			Score:     child.reward / float64(child.visits),
+			Rationale: fmt.Sprintf("won %.0f%% of %d playouts, %s", 100*child.reward/float64(child.visits), child.visits, rationale(gameState, p, candidate)),
+		})
+	}
+
+	//The most visited action is played, so it leads the ranking
+	slices.SortStableFunc(m.explanation, func(a, b RankedAction) int {
+		return cmp.Compare(root.children[actionKey(b.Action)].visits, root.children[actionKey(a.Action)].visits)
+	})
+
+	return bestAction
+}
+
+func (m *mctsController) Explain() []RankedAction {
+	return m.explanation
+}
+
+func (m *mctsController) DecideReplaceItem(gameState state, p player, newItem item, slot int) bool {
+	return m.fallback.DecideReplaceItem(gameState, p, newItem, slot)
+}
+
+func (m *mctsController) DecideDiscardPanicCard(gameState state, p player, panicCard card) bool {
+	return m.fallback.DecideDiscardPanicCard(gameState, p, panicCard)
+}
+
+func (m *mctsController) DecideTarget(gameState state, p player, usedItem item, targets []player) int {
+	return m.fallback.DecideTarget(gameState, p, usedItem, targets)
+}
+
+func (m *mctsController) DecideCancelPanic(gameState state, p player, usedItem item, panicType panicType) bool {
+	return m.fallback.DecideCancelPanic(gameState, p, usedItem, panicType)
+}
+
+func (m *mctsController) search(gameState state, p player, availableActions []actionType) *mctsNode {
+
+	root := newMCTSNode(action{})
+	sample := m.sampler(gameState, gameState.ActualPlayer)
+	start := time.Now()
+
+	for iteration := 0; ; iteration++ {
+		if m.config.Iterations > 0 && iteration >= m.config.Iterations {
+			break
+		}
+		if m.config.TimeBudget > 0 && time.Since(start) >= m.config.TimeBudget {
+			break
+		}
+		if m.config.Iterations <= 0 && m.config.TimeBudget <= 0 {
+			break
+		}
+
+		source := newRandomSource(m.randomizer.Int63())
+		sim := m.simulation(sample(rand.New(source)), source)
+		m.playout(&sim, root, availableActions)
+	}
+
+	return root
+}
+
+func (m *mctsController) simulation(sample state, source *randomSource) game {
+
+	//Without subscribers the playouts publish their events to nobody
+	sim := game{
+		state:      sample,
+		source:     source,
+		randomizer: rand.New(source),
+	}
+
+	rollout := newRolloutController(sim.source)
+	for i := range sim.state.Players {
+		sim.state.Players[i].Controller = rollout
+	}
+
+	return sim
+}
+
+func (m *mctsController) playout(sim *game, root *mctsNode, availableActions []actionType) {
+
+	playerIndex := sim.state.ActualPlayer
+	tree := &mctsTreePolicy{
+		node:        root,
+		path:        []*mctsNode{root},
+		exploration: m.config.Exploration,
+		rollout:     newRolloutController(sim.source),
+	}
+	sim.state.Players[playerIndex].Controller = tree
+
+	//The playout starts with the decision the search is made for
+	sim.turn = turn{
+		phase:            DecisionPhase,
+		availableActions: availableActions,
+	}
+	sim.play()
+
+	reward := 0.0
+	winners := sim.Winners()
+	for _, winner := range winners {
+		if winner == playerIndex {
+			reward = 1 / float64(len(winners))
+		}
+	}
+
+	for _, node := range tree.path {
+		node.visits++
+		node.reward += reward
+	}
+}
+
+// mctsTreePolicy walks down the tree for the decisions of the searching
+// player, expands one node and then plays randomly until the end of the game
+type mctsTreePolicy struct {
+	node        *mctsNode
+	path        []*mctsNode
+	exploration float64
+	rollout     *randomController
+}
+
+func (t *mctsTreePolicy) DecideAction(gameState state, p player, availableActions []actionType) action {
+
+	if t.node == nil {
+		return t.rollout.DecideAction(gameState, p, availableActions)
+	}
+
+	candidates := legalActions(gameState, p, availableActions)
+
+	unexplored := make([]action, 0)
+	for _, candidate := range candidates {
+		if _, found := t.node.children[actionKey(candidate)]; !found {
+			unexplored = append(unexplored, candidate)
+		}
+	}
+
+	if len(unexplored) > 0 {
+		expanded := unexplored[t.rollout.randomizer.Intn(len(unexplored))]
+		child := newMCTSNode(expanded)
+		t.node.children[actionKey(expanded)] = child
+		t.path = append(t.path, child)
+		t.node = nil
+		return expanded
+	}
+
+	var selected *mctsNode
+	bestValue := math.Inf(-1)
+	for _, candidate := range candidates {
+		child := t.node.children[actionKey(candidate)]
+		value := child.reward/float64(child.visits) + t.exploration*math.Sqrt(math.Log(float64(t.node.visits+1))/float64(child.visits))
+		if value > bestValue {
+			bestValue = value
+			selected = child
+		}
+	}
+
+	t.path = append(t.path, selected)
+	t.node = selected
+	return selected.action
+}
+
+func (t *mctsTreePolicy) DecideReplaceItem(gameState state, p player, newItem item, slot int) bool {
+	return t.rollout.DecideReplaceItem(gameState, p, newItem, slot)
+}
+
+func (t *mctsTreePolicy) DecideDiscardPanicCard(gameState state, p player, panicCard card) bool {
+	return t.rollout.DecideDiscardPanicCard(gameState, p, panicCard)
+}
+
+func (t *mctsTreePolicy) DecideTarget(gameState state, p player, usedItem item, targets []player) int {
+	return t.rollout.DecideTarget(gameState, p, usedItem, targets)
+}
+
+func (t *mctsTreePolicy) DecideCancelPanic(gameState state, p player, usedItem item, panicType panicType) bool {
+	return t.rollout.DecideCancelPanic(gameState, p, usedItem, panicType)
+}
@@ -0,0 +1,120 @@
+package model
+
+import (
+	"fmt"
+	"slices"
+)
+
+type costType string
+
+const (
+	MovementCost  costType = "MOVEMENT"
+	BreathingCost costType = "BREATHING"
+)
+
+// A costRule bounds a cost once every modifier is applied, when modifiers do
+// not stack only the strongest one counts. The minimum never raises a cost
+// that was already lower, breathing at level 10 stays free.
+type costRule struct {
+	minimum int
+	stacks  bool
+}
+
+var costRules = map[costType]costRule{
+	MovementCost: {minimum: 0, stacks: true},
+	//A diver always needs air and can only wear one mask at a time
+	BreathingCost: {minimum: 1, stacks: false},
+}
+
+// costModifiers are the item effects changing a cost. Passive ones apply as
+// long as the item is in the inventory, the others are charged by using the
+// item and spent on the next cost of their type.
+var costModifiers = map[itemEffectType]struct {
+	cost    costType
+	passive bool
+}{
+	MovementCostReduction: {cost: MovementCost, passive: true},
+	BreathCostReduction:   {cost: BreathingCost, passive: true},
+}
+
+type modifier struct {
+	source string
+	cost   costType
+	value  int
+}
+
+func (m modifier) String() string {
+	return fmt.Sprintf("%s -%d", m.source, m.value)
+}
+
+// modifiedCost runs the base cost through the modifiers of the player and
+// returns the modifiers that were applied
+func modifiedCost(p player, cost costType, base int) (int, []modifier) {
+
+	candidates := make([]modifier, 0)
+	for _, item := range p.Inventory {
+		if item == nil {
+			continue
+		}
+		for _, effect := range item.effects {
+			if found, ok := costModifiers[effect.effectType]; ok && found.passive && found.cost == cost {
+				candidates = append(candidates, modifier{source: item.name, cost: cost, value: effect.value})
+			}
+		}
+	}
+	for _, charged := range p.Modifiers {
+		if charged.cost == cost {
+			candidates = append(candidates, charged)
+		}
+	}
+
+	rule := costRules[cost]
+	applied := candidates
+	if !rule.stacks && len(candidates) > 1 {
+		strongest := candidates[0]
+		for _, candidate := range candidates[1:] {
+			if candidate.value > strongest.value {
+				strongest = candidate
+			}
+		}
+		applied = []modifier{strongest}
+	}
+
+	modified := base
+	for _, applied := range applied {
+		modified -= applied.value
+	}
+
+	return max(min(rule.minimum, base), modified), applied
+}
+
+// payCost gives the cost the player pays and spends the charged modifiers of
+// that cost
+func (g *game) payCost(p *player, cost costType, base int) int {
+
+	modified, applied := modifiedCost(*p, cost, base)
+	if len(applied) == 0 {
+		return modified
+	}
+
+	for _, spent := range applied {
+		if i := slices.Index(p.Modifiers, spent); i >= 0 {
+			p.Modifiers = slices.Delete(p.Modifiers, i, i+1)
+		}
+	}
+
+	g.publish(CostModified{Player: p.Id, Cost: cost, Base: base, Modified: modified, Modifiers: applied})
+
+	return modified
+}
+
+// chargeModifier keeps the effect of a used item for the next cost of its
+// type, it returns false when the effect is not a charged cost modifier
+func chargeModifier(p *player, source item, effect itemEffect) bool {
+	found, ok := costModifiers[effect.effectType]
+	if !ok || found.passive {
+		return false
+	}
+	p.Modifiers = append(p.Modifiers, modifier{source: source.name, cost: found.cost, value: effect.value})
+	return true
+}
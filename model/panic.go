@@ -1,5 +1,7 @@
 package model
 
+import "slices"
+
 type panicType string
 
 const (
@@ -11,11 +13,21 @@ const (
 	Purple panicType = "PURPLE"
 )
 
+var panicTypes = []panicType{Blue, Black, Red, Green, Yellow, Purple}
+
+// defaultPanicThreshold is used when the game sets no NumberOfPanicCardsToActivateEffect
+const defaultPanicThreshold = 3
+
 type panicCard struct {
 	genericCard
 	panicTypes []panicType
 }
 
+func (c panicCard) clone() panicCard {
+	c.panicTypes = slices.Clone(c.panicTypes)
+	return c
+}
+
 type panicEffectType string
 
 const (
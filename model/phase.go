@@ -0,0 +1,235 @@
+package model
+
+import (
+	"fmt"
+	"slices"
+)
+
+type Phase string
+
+const (
+	StartTurnPhase  Phase = "START_TURN"
+	BreathPhase     Phase = "BREATH"
+	PanicPhase      Phase = "PANIC"
+	EffectsPhase    Phase = "EFFECTS"
+	DecisionPhase   Phase = "DECISION"
+	ResolutionPhase Phase = "RESOLUTION"
+	AftermathPhase  Phase = "AFTERMATH"
+	EndTurnPhase    Phase = "END_TURN"
+	GameOverPhase   Phase = "GAME_OVER"
+)
+
+const decisionAttempts = 3
+
+type PhaseHook func(phase Phase, playerIndex int)
+
+// turn is where the engine stopped inside the turn of the actual player
+type turn struct {
+	phase            Phase
+	availableActions []actionType
+	action           *action
+	alive            []bool
+	//request is an undo or a redo asked instead of the action
+	request actionType
+}
+
+// clone copies the turn along with the action chosen for it
+func (t turn) clone() turn {
+	t.availableActions = slices.Clone(t.availableActions)
+	t.alive = slices.Clone(t.alive)
+	if t.action != nil {
+		actionToDo := t.action.clone()
+		t.action = &actionToDo
+	}
+	return t
+}
+
+type PendingDecision struct {
+	PlayerIndex      int
+	AvailableActions []actionType
+}
+
+func (g *game) Phase() Phase {
+	return g.turn.phase
+}
+
+// PendingDecision returns the player who must choose an action before the
+// engine can go on, as long as no action was submitted for the turn
+func (g *game) PendingDecision() (PendingDecision, bool) {
+	if g.turn.phase != DecisionPhase || g.turn.action != nil {
+		return PendingDecision{}, false
+	}
+	return PendingDecision{
+		PlayerIndex:      g.state.ActualPlayer,
+		AvailableActions: g.turn.availableActions,
+	}, true
+}
+
+// SubmitAction gives the action of the pending decision, the controller of
+// the player is only asked when the decision phase runs without one.
+// An illegal action is rejected with an ActionError and can be submitted again.
+func (g *game) SubmitAction(actionToDo action) error {
+	if _, pending := g.PendingDecision(); !pending {
+		return fmt.Errorf("no decision is pending in phase %s", g.turn.phase)
+	}
+	err := g.ValidateAction(g.state.ActualPlayer, actionToDo)
+	if err != nil {
+		return err
+	}
+	g.turn.action = &actionToDo
+	return nil
+}
+
+func (g *game) BeforePhase(hook PhaseHook) {
+	g.beforePhase = append(g.beforePhase, hook)
+}
+
+func (g *game) AfterPhase(hook PhaseHook) {
+	g.afterPhase = append(g.afterPhase, hook)
+}
+
+// Step runs the current phase and returns the next one
+func (g *game) Step() Phase {
+
+	phase := g.turn.phase
+	if phase == GameOverPhase {
+		return phase
+	}
+
+	playerIndex := g.state.ActualPlayer
+	for _, hook := range g.beforePhase {
+		hook(phase, playerIndex)
+	}
+
+	p := g.GetActualPlayer()
+	next := g.runPhase(phase, p)
+
+	for _, hook := range g.afterPhase {
+		hook(phase, playerIndex)
+	}
+
+	g.turn.phase = next
+
+	if request := g.turn.request; request != "" {
+		g.turn.request = ""
+		g.answerRequest(p.Id, request)
+	}
+
+	return g.turn.phase
+}
+
+func (g *game) runPhase(phase Phase, p *player) Phase {
+
+	switch phase {
+	case StartTurnPhase:
+		if g.IsGameEnded() || g.IsRoundLimitReached() {
+			return GameOverPhase
+		}
+		if g.state.ActualPlayer == 0 {
+			g.publish(RoundStarted{Round: g.state.Round})
+		}
+
+		g.turn = turn{alive: make([]bool, len(g.state.Players))}
+		for i, player := range g.state.Players {
+			g.turn.alive[i] = !player.IsDead()
+		}
+
+		//The dead divers have no turn anymore
+		if p.IsDead() {
+			return EndTurnPhase
+		}
+
+		g.publish(TurnStarted{Player: p.Id, Level: p.DiveLevel, Inventory: inventory(p)})
+		return BreathPhase
+
+	case BreathPhase:
+		cards := g.breath(p)
+		recordCauseOfDeath(p, string(Breath))
+		p.KeepPanicCards(cards)
+		g.publish(Breathed{Player: p.Id, Cards: cards, Hand: slices.Clone(p.HandCards)})
+		if p.IsDead() {
+			return EndTurnPhase
+		}
+		return PanicPhase
+
+	case PanicPhase:
+		g.checkPanic(p)
+		return EffectsPhase
+
+	case EffectsPhase:
+		g.turn.availableActions = p.CheckPlayerEffects()
+		g.publish(ActionsAvailable{Player: p.Id, Actions: g.turn.availableActions})
+		return DecisionPhase
+
+	case DecisionPhase:
+		if g.turn.action != nil {
+			g.publish(ActionChosen{Player: p.Id, Action: *g.turn.action})
+			return ResolutionPhase
+		}
+		actionToDo := g.decideAction(p)
+		if isHistoryRequest(actionToDo) {
+			g.turn.request = actionToDo.actionType
+			return DecisionPhase
+		}
+		g.turn.action = &actionToDo
+		chosen := ActionChosen{Player: p.Id, Action: actionToDo}
+		if explainer, ok := p.Controller.(ExplainingController); ok {
+			chosen.Explanation = explainer.Explain()
+		}
+		g.publish(chosen)
+		return ResolutionPhase
+
+	case ResolutionPhase:
+		err := g.resolveAction(p, *g.turn.action)
+		if err != nil {
+			g.publish(ActionRejected{Player: p.Id, Action: *g.turn.action, Err: err})
+		}
+		g.publish(ActionResolved{Player: p.Id, Inventory: inventory(p), Hand: slices.Clone(p.HandCards)})
+		return AftermathPhase
+
+	case AftermathPhase:
+		g.checkPanic(p)
+		g.publish(TurnEnded{Player: p.Id})
+		return EndTurnPhase
+
+	case EndTurnPhase:
+		//A diver can also run out of oxygen during the turn of another one
+		for i, player := range g.state.Players {
+			if i < len(g.turn.alive) && g.turn.alive[i] && player.IsDead() {
+				g.publish(PlayerDied{Player: player.Id, Level: player.DiveLevel, Round: g.state.Round})
+			}
+		}
+
+		if g.state.ActualPlayer == len(g.state.Players)-1 {
+			g.publish(RoundEnded{Round: g.state.Round})
+		}
+
+		if g.IsGameEnded() {
+			return GameOverPhase
+		}
+
+		g.state.NextPlayer()
+		return StartTurnPhase
+	}
+
+	return phase
+}
+
+// decideAction asks the controller again when it chooses an illegal action,
+// after the last attempt the first legal action is played instead
+func (g *game) decideAction(p *player) action {
+
+	for range decisionAttempts {
+		actionToDo := p.DecideActionToDo(g.state, g.turn.availableActions)
+		if isHistoryRequest(actionToDo) {
+			return actionToDo
+		}
+		err := validateAction(g.state, *p, g.turn.availableActions, actionToDo)
+		if err == nil {
+			return actionToDo
+		}
+		g.publish(ActionRejected{Player: p.Id, Action: actionToDo, Err: err})
+	}
+
+	return legalActions(g.state, *p, g.turn.availableActions)[0]
+}
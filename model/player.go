@@ -1,12 +1,8 @@
 package model
 
 import (
-	"bufio"
-	"fmt"
-	"os"
+	"maps"
 	"slices"
-	"strconv"
-	"strings"
 )
 
 type playerEffect string
@@ -27,6 +23,11 @@ type player struct {
 	DiscardedObjects []item
 	DiveLevel        int
 	ActiveEffects    map[playerEffect]int
+	KnownO2Cards     int
+	PanicActivations int
+	CauseOfDeath     string
+	Modifiers        []modifier
+	Controller       PlayerController
 }
 
 func NewPlayer(id string, inventorySlot int) player {
@@ -42,6 +43,24 @@ func NewPlayer(id string, inventorySlot int) player {
 	}
 }
 
+func (p player) clone() player {
+	clone := p
+	clone.OxygenCards = slices.Clone(p.OxygenCards)
+	clone.HandCards = slices.Clone(p.HandCards)
+	clone.DiscardedCards = slices.Clone(p.DiscardedCards)
+	clone.DiscardedObjects = slices.Clone(p.DiscardedObjects)
+	clone.ActiveEffects = maps.Clone(p.ActiveEffects)
+	clone.Modifiers = slices.Clone(p.Modifiers)
+	clone.Inventory = make([]*item, len(p.Inventory))
+	for i, item := range p.Inventory {
+		if item != nil {
+			copied := item.clone()
+			clone.Inventory[i] = &copied
+		}
+	}
+	return clone
+}
+
 func (p *player) Draw(numberOfCards int) []card {
 
 	if len(p.OxygenCards) <= numberOfCards {
@@ -55,6 +74,8 @@ func (p *player) Draw(numberOfCards int) []card {
 		p.OxygenCards = p.OxygenCards[1:]
 	}
 
+	p.KnownO2Cards = max(0, p.KnownO2Cards-numberOfCards)
+
 	return drawedCards
 }
 
@@ -62,95 +83,54 @@ func (p *player) Discard(cards []card) {
 	p.DiscardedCards = append(p.DiscardedCards, cards...)
 }
 
-func (p *player) Breath() []card {
+func (p *player) KeepPanicCards(cards []card) {
+	for _, card := range cards {
+		if card.GetType() == PanicType {
+			p.HandCards = append(p.HandCards, card)
+		} else {
+			p.DiscardedCards = append(p.DiscardedCards, card)
+		}
+	}
+}
+
+func BreathCost(diveLevel int) int {
 
 	var oxygenNeeded int
 	switch {
-	case p.DiveLevel >= 1 && p.DiveLevel <= 3:
+	case diveLevel >= 1 && diveLevel <= 3:
 		oxygenNeeded = 1
-	case p.DiveLevel >= 4 && p.DiveLevel <= 6:
+	case diveLevel >= 4 && diveLevel <= 6:
 		oxygenNeeded = 2
-	case p.DiveLevel >= 7 && p.DiveLevel <= 9:
+	case diveLevel >= 7 && diveLevel <= 9:
 		oxygenNeeded = 3
 	}
 
-	return p.Draw(oxygenNeeded)
+	return oxygenNeeded
 }
 
 func (p player) DecideActionToDo(gameState state, availableActions []actionType) action {
+	return p.Controller.DecideAction(gameState, p, availableActions)
+}
 
-	reader := bufio.NewScanner(os.Stdin)
-
-	for {
-		fmt.Printf("\tChoose action: A=ascend, D=dive, E=explore, C=calm, U=use object H=Hold\n")
-		fmt.Printf("\tAnswer:")
-		reader.Scan()
-		answer := strings.TrimSpace(strings.ToUpper(reader.Text()))
-
-		readActionParam := strings.Split(answer, " ")
-
-		switch strings.ToUpper(readActionParam[0]) {
-		case "A":
-			value, err := strconv.Atoi(readActionParam[1])
-			if err != nil {
-				fmt.Printf("\tAction argument must be an integer\n")
-				continue
-			}
-			if value < 1 || value > 3 {
-				fmt.Printf("\tAction argument must be between 1 and 3\n")
-				continue
-			}
-			return NewAction(Ascend, map[actionParam]int{AscendLevels: value})
-		case "D":
-			value, err := strconv.Atoi(readActionParam[1])
-			if err != nil {
-				fmt.Printf("\tAction argument must be an integer\n")
-				continue
-			}
-			if value < 1 || value > 3 {
-				fmt.Printf("\tAction argument must be between 1 and 3\n")
-				continue
-			}
-			return NewAction(Dive, map[actionParam]int{DiveLevels: value})
-		case "E":
-			value, err := strconv.Atoi(readActionParam[1])
-			if err != nil {
-				fmt.Printf("\tAction argument must be an integer\n")
-				continue
-			}
-			if value < 1 || value > 3 {
-				fmt.Printf("\tAction argument must be between 1 and 3\n")
-				continue
-			}
-			return NewAction(Explore, map[actionParam]int{ExploreTime: value})
-		case "C":
-			return NewAction(CalmDown, map[actionParam]int{})
-		case "U":
-			value, err := strconv.Atoi(readActionParam[1])
-			if err != nil {
-				fmt.Printf("\tAction argument must be an integer\n")
-				continue
-			}
-			if value < 1 || value > len(p.Inventory) {
-				fmt.Printf("\tAction argument must be between 1 and 3\n")
-				continue
-			}
-			return NewAction(UseObject, map[actionParam]int{ItemToUse: value})
-		case "H":
-			return NewAction(UseObject, map[actionParam]int{})
-		default:
-			fmt.Printf("\t'%s' is not a valid action\n", readActionParam[0])
-			continue
+func (p player) Score() int {
+	score := 0
+	for _, item := range p.Inventory {
+		if item != nil && item.itemType != Utility {
+			score += item.quantity
 		}
 	}
-
+	return score
 }
 
 func (p player) IsDead() bool {
 	return len(p.OxygenCards) == 0
 }
 
-func (p *player) CheckPanic() []panicEffect {
+// CheckPanic activates the panic types with threshold cards in hand, cancel can
+// stop an activation right before it happens. The cards of a cancelled panic
+// leave the hand as if it was activated so that it does not fire next turn.
+func (p *player) CheckPanic(threshold int, resolutionOrder []panicType, cancel func(panicType panicType) bool) ([]panicType, []panicEffect) {
+	activatedPanics := make([]panicType, 0)
 	panicEffects := make([]panicEffect, 0)
 
 	panics := map[panicType]int{
@@ -169,25 +149,42 @@ func (p *player) CheckPanic() []panicEffect {
 		}
 	}
 
-	for panicType, counter := range panics {
-		if counter >= 3 {
-			fmt.Printf("\tActivate level %d of '%s' panic type\n", p.DiveLevel, panicType)
-			panicEffects = append(panicEffects, panicActivationEffects[panicType][p.DiveLevel]...)
+	for _, panicType := range resolutionOrder {
+		if panics[panicType] >= threshold {
+			cancelled := cancel(panicType)
+			if !cancelled {
+				activatedPanics = append(activatedPanics, panicType)
+				panicEffects = append(panicEffects, panicActivationEffects[panicType][p.DiveLevel]...)
+			}
 
 			// Iterate backwards to safely remove elements
 			for i := len(p.HandCards) - 1; i >= 0; i-- {
 				card := p.HandCards[i]
 				if slices.Contains(card.(panicCard).panicTypes, panicType) {
 					p.HandCards = append(p.HandCards[:i], p.HandCards[i+1:]...)
+					//The cards of a cancelled panic are discarded like calmed down ones
+					if cancelled {
+						p.DiscardedCards = append(p.DiscardedCards, card)
+					}
 				}
 			}
 		}
 	}
 
-	return panicEffects
+	return activatedPanics, panicEffects
 }
 
-func (p *player) CheckPlayerEffects() []actionType {
+// allowedActionTypes lists the action types left by the active effects
+// without making them expire
+func (p player) allowedActionTypes() []actionType {
+
+	if _, found := p.ActiveEffects[SkipTurn]; found {
+		return make([]actionType, 0)
+	}
+
+	if _, found := p.ActiveEffects[HaveToCalmDown]; found {
+		return []actionType{CalmDown}
+	}
 
 	availableAction := []actionType{
 		Ascend,
@@ -199,31 +196,44 @@ func (p *player) CheckPlayerEffects() []actionType {
 	}
 
 	notAvailableMoves := make([]actionType, 0)
+	if _, found := p.ActiveEffects[CantMove]; found {
+		notAvailableMoves = append(notAvailableMoves, Ascend, Dive)
+	}
+	if _, found := p.ActiveEffects[CantExplore]; found {
+		notAvailableMoves = append(notAvailableMoves, Explore)
+	}
 
-	for effectType, value := range p.ActiveEffects {
-		switch effectType {
-		case SkipTurn:
-			newValue := value - 1
-			if newValue > 0 {
-				p.ActiveEffects[effectType] = newValue
-			} else {
-				delete(p.ActiveEffects, effectType)
-			}
+	return SubtractSlices(availableAction, notAvailableMoves)
+}
 
-			return make([]actionType, 0)
-		case CantMove:
-			notAvailableMoves = append(notAvailableMoves, Ascend, Dive)
-			delete(p.ActiveEffects, effectType)
-		case CantExplore:
-			notAvailableMoves = append(notAvailableMoves, Ascend, Dive)
-			delete(p.ActiveEffects, effectType)
-		case HaveToCalmDown:
-			delete(p.ActiveEffects, effectType)
-			return []actionType{CalmDown}
-		}
+func (p *player) CheckPlayerEffects() []actionType {
+
+	availableActions := p.allowedActionTypes()
+
+	//A skipped turn leaves the other effects for the next turn
+	if _, found := p.ActiveEffects[SkipTurn]; found {
+		p.wearOff(SkipTurn)
+		return availableActions
 	}
 
-	return SubtractSlices(availableAction, notAvailableMoves)
+	p.wearOff(CantMove)
+	p.wearOff(CantExplore)
+	p.wearOff(HaveToCalmDown)
+
+	return availableActions
+}
+
+// wearOff takes one turn from the duration of the effect
+func (p *player) wearOff(effect playerEffect) {
+	value, found := p.ActiveEffects[effect]
+	if !found {
+		return
+	}
+	if value > 1 {
+		p.ActiveEffects[effect] = value - 1
+	} else {
+		delete(p.ActiveEffects, effect)
+	}
 }
 
 func SubtractSlices(fullActionTypeList, prohibitedActionTypes []actionType) []actionType {
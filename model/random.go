@@ -0,0 +1,110 @@
+package model
+
+import (
+	"math/rand"
+	"slices"
+)
+
+type randomController struct {
+	source      *randomSource
+	randomizer  *rand.Rand
+	explain     bool
+	explanation []RankedAction
+}
+
+// The controller draws from its own source seeded by the randomizer, so that
+// a clone can go on with the same numbers
+func NewRandomController(randomizer *rand.Rand) *randomController {
+	source := newRandomSource(randomizer.Int63())
+	return &randomController{
+		source:     source,
+		randomizer: rand.New(source),
+		explain:    true,
+	}
+}
+
+// The rollouts of the search bots draw from the source of their playout
+func newRolloutController(source *randomSource) *randomController {
+	return &randomController{
+		source:     source,
+		randomizer: rand.New(source),
+	}
+}
+
+func (r *randomController) clone() PlayerController {
+	clone := *r
+	source := *r.source
+	clone.source = &source
+	clone.randomizer = rand.New(&source)
+	clone.explanation = nil
+	return &clone
+}
+
+func (r *randomController) DecideAction(gameState state, p player, availableActions []actionType) action {
+
+	actionToDo := r.decideAction(gameState, p, availableActions)
+
+	//Rollouts of the search bots skip the explanation
+	if r.explain {
+		r.explanation = []RankedAction{{
+			Action:    actionToDo,
+			Score:     1,
+			Rationale: "chosen at random, " + rationale(gameState, p, actionToDo),
+		}}
+		for _, candidate := range legalActions(gameState, p, availableActions) {
+			if actionKey(candidate) != actionKey(actionToDo) {
+				r.explanation = append(r.explanation, RankedAction{
+					Action:    candidate,
+					Score:     0,
+					Rationale: rationale(gameState, p, candidate),
+				})
+			}
+		}
+	}
+
+	return actionToDo
+}
+
+func (r *randomController) Explain() []RankedAction {
+	return r.explanation
+}
+
+// The type is drawn first so that the actions with many parameters are not
+// played more often than the others
+func (r *randomController) decideAction(gameState state, p player, availableActions []actionType) action {
+
+	legal := legalActions(gameState, p, availableActions)
+
+	types := make([]actionType, 0)
+	for _, candidate := range legal {
+		if !slices.Contains(types, candidate.actionType) {
+			types = append(types, candidate.actionType)
+		}
+	}
+	actionType := types[r.randomizer.Intn(len(types))]
+
+	ofType := make([]action, 0)
+	for _, candidate := range legal {
+		if candidate.actionType == actionType {
+			ofType = append(ofType, candidate)
+		}
+	}
+
+	return ofType[r.randomizer.Intn(len(ofType))]
+}
+
+func (r *randomController) DecideReplaceItem(gameState state, p player, newItem item, slot int) bool {
+	return r.randomizer.Intn(2) == 0
+}
+
+func (r *randomController) DecideDiscardPanicCard(gameState state, p player, panicCard card) bool {
+	return r.randomizer.Intn(2) == 0
+}
+
+func (r *randomController) DecideTarget(gameState state, p player, usedItem item, targets []player) int {
+	return r.randomizer.Intn(len(targets))
+}
+
+func (r *randomController) DecideCancelPanic(gameState state, p player, usedItem item, panicType panicType) bool {
+	return r.randomizer.Intn(2) == 0
+}
@@ -0,0 +1,87 @@
+package model
+
+type EndReason string
+
+const (
+	NotEnded        EndReason = "NOT_ENDED"
+	AllDiversDead   EndReason = "ALL_DIVERS_DEAD"
+	DavyJonesKilled EndReason = "DAVY_JONES_KILLED"
+	//RoundLimitReached games end with the richest divers as winners
+	RoundLimitReached EndReason = "ROUND_LIMIT_REACHED"
+)
+
+type GameResult struct {
+	EndReason EndReason      `json:"endReason"`
+	Winners   []string       `json:"winners"`
+	Rounds    int            `json:"rounds"`
+	Players   []PlayerResult `json:"players"`
+}
+
+type PlayerResult struct {
+	Id               string   `json:"id"`
+	Level            int      `json:"level"`
+	Oxygen           int      `json:"oxygen"`
+	Items            []string `json:"items"`
+	Score            int      `json:"score"`
+	PanicActivations int      `json:"panicActivations"`
+	Dead             bool     `json:"dead"`
+	CauseOfDeath     string   `json:"causeOfDeath,omitempty"`
+}
+
+// Result describes the game as it is now, the winners are only known once
+// the game has ended
+func (g *game) Result() GameResult {
+
+	result := GameResult{
+		EndReason: NotEnded,
+		Winners:   make([]string, 0),
+		Rounds:    g.state.Round,
+		Players:   make([]PlayerResult, len(g.state.Players)),
+	}
+
+	switch {
+	case g.IsDavyJonesIsDead():
+		result.EndReason = DavyJonesKilled
+	case g.AreAllPlayersDead():
+		result.EndReason = AllDiversDead
+	case g.IsRoundLimitReached():
+		result.EndReason = RoundLimitReached
+		result.Rounds = g.parameters.values[MaxRounds]
+	}
+
+	if result.EndReason != NotEnded {
+		for _, winner := range g.Winners() {
+			result.Winners = append(result.Winners, g.state.Players[winner].Id)
+		}
+	}
+
+	for i, player := range g.state.Players {
+		items := make([]string, 0)
+		for _, item := range player.Inventory {
+			if item != nil {
+				items = append(items, item.name)
+			}
+		}
+		result.Players[i] = PlayerResult{
+			Id:               player.Id,
+			Level:            player.DiveLevel,
+			Oxygen:           len(player.OxygenCards),
+			Items:            items,
+			Score:            player.Score(),
+			PanicActivations: player.PanicActivations,
+			Dead:             player.IsDead(),
+			CauseOfDeath:     player.CauseOfDeath,
+		}
+	}
+
+	return result
+}
+
+func (r GameResult) IsWinner(playerId string) bool {
+	for _, winner := range r.Winners {
+		if winner == playerId {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,129 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+type scriptedController struct {
+	decisions []string
+	next      int
+}
+
+func NewScriptedController(decisions []string) *scriptedController {
+	return &scriptedController{
+		decisions: decisions,
+	}
+}
+
+// A script is either a JSON array of answers or a text file with one answer
+// per line, blank lines and lines starting with '#' are skipped
+func LoadScriptedController(path string) (*scriptedController, error) {
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	decisions := make([]string, 0)
+
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		err = json.Unmarshal(data, &decisions)
+		if err != nil {
+			return nil, err
+		}
+		return NewScriptedController(decisions), nil
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		decisions = append(decisions, line)
+	}
+
+	return NewScriptedController(decisions), nil
+}
+
+// The clone goes on from the same decision, the original does not move
+func (s *scriptedController) clone() PlayerController {
+	clone := *s
+	return &clone
+}
+
+func (s *scriptedController) nextDecision(p player, expected string) string {
+	if s.next >= len(s.decisions) {
+		panic(fmt.Sprintf("script ended: player '%s' must decide %s but decision %d is missing", p.Id, expected, s.next+1))
+	}
+	decision := strings.TrimSpace(strings.ToUpper(s.decisions[s.next]))
+	s.next++
+	return decision
+}
+
+func (s *scriptedController) failf(p player, decision string, format string, a ...any) {
+	panic(fmt.Sprintf("script decision %d '%s' of player '%s': %s", s.next, decision, p.Id, fmt.Sprintf(format, a...)))
+}
+
+func (s *scriptedController) DecideAction(gameState state, p player, availableActions []actionType) action {
+
+	decision := s.nextDecision(p, "an action")
+
+	actionToDo, err := parseAction(decision, p)
+	if err != nil {
+		s.failf(p, decision, "%s", err)
+	}
+
+	err = validateAction(gameState, p, availableActions, actionToDo)
+	if err != nil {
+		s.failf(p, decision, "%s", err)
+	}
+
+	return actionToDo
+}
+
+func (s *scriptedController) readYesNo(p player, question string) bool {
+
+	decision := s.nextDecision(p, question)
+
+	switch decision {
+	case "Y":
+		return true
+	case "N":
+		return false
+	}
+
+	s.failf(p, decision, "expected Y or N to answer %s", question)
+	return false
+}
+
+func (s *scriptedController) DecideReplaceItem(gameState state, p player, newItem item, slot int) bool {
+	return s.readYesNo(p, fmt.Sprintf("whether to keep item '%s' and drop item '%s'", newItem.name, p.Inventory[slot].name))
+}
+
+func (s *scriptedController) DecideDiscardPanicCard(gameState state, p player, panicCard card) bool {
+	return s.readYesNo(p, fmt.Sprintf("whether to discard panic card '%s'", panicCard.GetName()))
+}
+
+func (s *scriptedController) DecideCancelPanic(gameState state, p player, usedItem item, panicType panicType) bool {
+	return s.readYesNo(p, fmt.Sprintf("whether to use item '%s' to cancel panic '%s'", usedItem.name, panicType))
+}
+
+// The target is answered with its position in the list or with its id
+func (s *scriptedController) DecideTarget(gameState state, p player, usedItem item, targets []player) int {
+
+	decision := s.nextDecision(p, fmt.Sprintf("who to catch with '%s'", usedItem.name))
+
+	for i, target := range targets {
+		if decision == strconv.Itoa(i+1) || strings.EqualFold(decision, target.Id) {
+			return i
+		}
+	}
+
+	s.failf(p, decision, "expected a number between 1 and %d or the id of a diver within one level", len(targets))
+	return 0
+}
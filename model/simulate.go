@@ -0,0 +1,152 @@
+package model
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"runtime"
+	"slices"
+	"sync"
+	"time"
+)
+
+type SimulationConfig struct {
+	Entrants       []string
+	Games          int
+	PlayersPerGame int
+	Workers        int
+	Seed           int64
+}
+
+type SimulationRecord struct {
+	Game        int      `json:"game"`
+	Seed        int64    `json:"seed"`
+	Controllers []string `json:"controllers"`
+	GameResult
+}
+
+type simulationJob struct {
+	number int
+	seed   int64
+	seats  []int
+}
+
+const progressInterval = 500 * time.Millisecond
+
+// Simulate plays the games on a pool of workers, every game owns its state,
+// decks and randomizers so the workers share nothing but the job queue.
+// Records are written as soon as their game ends, so they are not sorted.
+func Simulate(config SimulationConfig, records io.Writer, progress io.Writer, parameters ...gameParameter) error {
+
+	for _, name := range config.Entrants {
+		if _, found := registeredControllers[name]; !found {
+			return fmt.Errorf("unknown controller '%s', registered controllers are %v", name, RegisteredControllers())
+		}
+	}
+
+	if config.PlayersPerGame <= 0 || config.PlayersPerGame > len(config.Entrants) {
+		config.PlayersPerGame = len(config.Entrants)
+	}
+	if config.PlayersPerGame < 2 {
+		return fmt.Errorf("a simulation needs at least 2 players per game")
+	}
+	if config.Workers <= 0 {
+		config.Workers = runtime.NumCPU()
+	}
+
+	parameters = append(slices.Clone(parameters), NewGameParameter(NumberOfPlayers, config.PlayersPerGame))
+
+	jobs := make(chan simulationJob)
+	results := make(chan SimulationRecord)
+
+	//Seeds are drawn in order so a run does not depend on the number of workers
+	go func() {
+		randomizer := rand.New(rand.NewSource(config.Seed))
+		for i := range config.Games {
+			seats := make([]int, config.PlayersPerGame)
+			for j := range seats {
+				seats[j] = (i + j) % len(config.Entrants)
+			}
+			jobs <- simulationJob{
+				number: i + 1,
+				seed:   randomizer.Int63(),
+				seats:  seats,
+			}
+		}
+		close(jobs)
+	}()
+
+	var workers sync.WaitGroup
+	for range config.Workers {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for job := range jobs {
+				results <- simulateGame(config, job, parameters)
+			}
+		}()
+	}
+
+	go func() {
+		workers.Wait()
+		close(results)
+	}()
+
+	writer := bufio.NewWriter(records)
+	encoder := json.NewEncoder(writer)
+
+	var err error
+	start := time.Now()
+	lastProgress := start
+	done := 0
+
+	for record := range results {
+		done++
+		//After a write error the games still have to be drained to stop the workers
+		if err == nil {
+			err = encoder.Encode(record)
+		}
+		if now := time.Now(); now.Sub(lastProgress) >= progressInterval || done == config.Games {
+			lastProgress = now
+			printProgress(progress, done, config.Games, now.Sub(start))
+		}
+	}
+	fmt.Fprintln(progress)
+
+	if err != nil {
+		return err
+	}
+
+	return writer.Flush()
+}
+
+func simulateGame(config SimulationConfig, job simulationJob, parameters []gameParameter) SimulationRecord {
+
+	g := NewGame(append(slices.Clone(parameters), NewGameParameter(Seed, int(job.seed)))...)
+	g.SetOutput(io.Discard)
+	for j, entrant := range job.seats {
+		controller, _ := NewRegisteredController(config.Entrants[entrant], g.NewRandomizer())
+		g.SetController(j, controller)
+	}
+
+	record := SimulationRecord{
+		Game:        job.number,
+		Seed:        job.seed,
+		Controllers: make([]string, len(job.seats)),
+		GameResult:  g.Run(1)[0],
+	}
+	for j, entrant := range job.seats {
+		record.Controllers[j] = config.Entrants[entrant]
+	}
+
+	return record
+}
+
+func printProgress(out io.Writer, done int, games int, elapsed time.Duration) {
+	rate := float64(done) / max(elapsed.Seconds(), 0.001)
+	remaining := time.Duration(float64(games-done) / rate * float64(time.Second))
+	fmt.Fprintf(out, "\rSimulated %d/%d games (%.0f%%), %.1f games/s, %s left   ",
+		done, games, 100*float64(done)/float64(max(games, 1)), rate, remaining.Round(time.Second))
+}
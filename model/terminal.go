@@ -0,0 +1,184 @@
+package model
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type terminalController struct {
+	reader *bufio.Scanner
+	hints  *mctsController
+}
+
+func NewTerminalController(input io.Reader) *terminalController {
+	return &terminalController{
+		reader: bufio.NewScanner(input),
+	}
+}
+
+func (t *terminalController) readAnswer() string {
+	t.reader.Scan()
+	return strings.TrimSpace(strings.ToUpper(t.reader.Text()))
+}
+
+func (t *terminalController) readYesNo(question string) bool {
+	for {
+		fmt.Printf("\t%s (Y/N): ", question)
+		answer := t.readAnswer()
+
+		if answer == "Y" {
+			return true
+		} else if answer == "N" {
+			return false
+		} else {
+			fmt.Printf("\tPlease answer with Y or N.\n")
+		}
+	}
+}
+
+func (t *terminalController) DecideAction(gameState state, p player, availableActions []actionType) action {
+
+	for {
+		fmt.Printf("\tChoose action: A=ascend, D=dive, E=explore, C=calm, X=distract, U=use object H=Hold ?=hint Z=undo R=redo\n")
+		fmt.Printf("\tAnswer:")
+		answer := t.readAnswer()
+
+		switch answer {
+		case "?":
+			t.printHint(gameState, p, availableActions)
+			continue
+		case "Z":
+			return NewAction(UndoRequest, map[actionParam]int{})
+		case "R":
+			return NewAction(RedoRequest, map[actionParam]int{})
+		}
+
+		actionToDo, err := parseAction(answer, p)
+		if err == nil {
+			err = validateAction(gameState, p, availableActions, actionToDo)
+		}
+		if err != nil {
+			fmt.Printf("\t%s\n", err)
+			continue
+		}
+
+		return actionToDo
+	}
+}
+
+// The hint searches on states sampled from what the player can see, with its
+// own randomizer so that asking for a hint never changes the game
+func (t *terminalController) printHint(gameState state, p player, availableActions []actionType) {
+
+	if t.hints == nil {
+		t.hints = NewInformationSetController(DefaultMCTSConfig(), rand.New(rand.NewSource(time.Now().UnixNano())))
+	}
+
+	t.hints.DecideAction(gameState, p, availableActions)
+	ranking := t.hints.Explain()
+	if len(ranking) == 0 {
+		fmt.Printf("\tNo hint available\n")
+		return
+	}
+
+	fmt.Printf("\tHint: %s (estimated win chance %.0f%%)\n", describeAction(ranking[0].Action), 100*ranking[0].Score)
+	for i, ranked := range ranking {
+		if i == explanationLength {
+			break
+		}
+		fmt.Printf("\t\t%d. %s\n", i+1, ranked.Rationale)
+	}
+}
+
+func parseAction(answer string, p player) (action, error) {
+
+	readActionParam := strings.Split(strings.TrimSpace(strings.ToUpper(answer)), " ")
+
+	readValue := func(minValue int, maxValue int) (int, error) {
+		if len(readActionParam) < 2 {
+			return 0, fmt.Errorf("%w: the argument is missing", ErrInvalidArgument)
+		}
+		value, err := strconv.Atoi(readActionParam[1])
+		if err != nil {
+			return 0, fmt.Errorf("%w: the argument must be an integer", ErrInvalidArgument)
+		}
+		if value < minValue || value > maxValue {
+			return 0, fmt.Errorf("%w: the argument must be between %d and %d", ErrInvalidArgument, minValue, maxValue)
+		}
+		return value, nil
+	}
+
+	switch readActionParam[0] {
+	case "A":
+		value, err := readValue(1, 3)
+		if err != nil {
+			return action{}, err
+		}
+		return NewAction(Ascend, map[actionParam]int{AscendLevels: value}), nil
+	case "D":
+		value, err := readValue(1, 3)
+		if err != nil {
+			return action{}, err
+		}
+		return NewAction(Dive, map[actionParam]int{DiveLevels: value}), nil
+	case "E":
+		value, err := readValue(1, 3)
+		if err != nil {
+			return action{}, err
+		}
+		return NewAction(Explore, map[actionParam]int{ExploreTime: value}), nil
+	case "C":
+		return NewAction(CalmDown, map[actionParam]int{}), nil
+	case "X":
+		return NewAction(Distract, map[actionParam]int{}), nil
+	case "U":
+		value, err := readValue(1, len(p.Inventory))
+		if err != nil {
+			return action{}, err
+		}
+		return NewAction(UseObject, map[actionParam]int{ItemToUse: value}), nil
+	case "H":
+		return NewAction(UseObject, map[actionParam]int{}), nil
+	}
+
+	return action{}, fmt.Errorf("%w: '%s'", ErrUnknownAction, readActionParam[0])
+}
+
+func (t *terminalController) ObserveO2Cards(gameState state, p player, cards []card) {
+	fmt.Printf("\tNext oxygen cards:\n")
+	for _, card := range cards {
+		fmt.Printf("\t\t%s\n", card.GetName())
+	}
+}
+
+func (t *terminalController) DecideReplaceItem(gameState state, p player, newItem item, slot int) bool {
+	return t.readYesNo(fmt.Sprintf("Do you want to keep item '%s' and drop item '%s'?", newItem.name, p.Inventory[slot].name))
+}
+
+func (t *terminalController) DecideTarget(gameState state, p player, usedItem item, targets []player) int {
+	for {
+		fmt.Printf("\tWho do you want to catch with '%s'?\n", usedItem.name)
+		for i, target := range targets {
+			fmt.Printf("\t\t%d. %s (level %d)\n", i+1, target.Id, target.DiveLevel)
+		}
+		fmt.Printf("\tAnswer:")
+		choice, err := strconv.Atoi(t.readAnswer())
+		if err == nil && choice >= 1 && choice <= len(targets) {
+			return choice - 1
+		}
+		fmt.Printf("\tPlease answer with a number between 1 and %d.\n", len(targets))
+	}
+}
+
+func (t *terminalController) DecideCancelPanic(gameState state, p player, usedItem item, panicType panicType) bool {
+	return t.readYesNo(fmt.Sprintf("Panic '%s' is about to activate, do you want to use item '%s' to cancel it?", panicType, usedItem.name))
+}
+
+func (t *terminalController) DecideDiscardPanicCard(gameState state, p player, panicCard card) bool {
+	return t.readYesNo(fmt.Sprintf("Do you want to discard panic card '%s'?", panicCard.GetName()))
+}
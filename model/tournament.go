@@ -0,0 +1,156 @@
+package model
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"slices"
+)
+
+type TournamentConfig struct {
+	Entrants       []string
+	Games          int
+	PlayersPerGame int
+	Seed           int64
+}
+
+type TournamentEntry struct {
+	Name   string
+	Games  int
+	Wins   float64
+	Rounds int
+	Deaths int
+	//Capped counts the games stopped by the round limit
+	Capped int
+	Rating float64
+}
+
+const (
+	initialRating = 1500
+	ratingFactor  = 32
+)
+
+func RunTournament(config TournamentConfig, parameters ...gameParameter) ([]TournamentEntry, error) {
+
+	for _, name := range config.Entrants {
+		if _, found := registeredControllers[name]; !found {
+			return nil, fmt.Errorf("unknown controller '%s', registered controllers are %v", name, RegisteredControllers())
+		}
+	}
+
+	if config.PlayersPerGame <= 0 || config.PlayersPerGame > len(config.Entrants) {
+		config.PlayersPerGame = len(config.Entrants)
+	}
+	if config.PlayersPerGame < 2 {
+		return nil, fmt.Errorf("a tournament needs at least 2 players per game")
+	}
+
+	entries := make([]TournamentEntry, len(config.Entrants))
+	for i, name := range config.Entrants {
+		entries[i] = TournamentEntry{
+			Name:   name,
+			Rating: initialRating,
+		}
+	}
+
+	parameters = append(slices.Clone(parameters), NewGameParameter(NumberOfPlayers, config.PlayersPerGame))
+
+	randomizer := rand.New(rand.NewSource(config.Seed))
+	var seed int64
+
+	for i := range config.Games {
+		//Every deal is played once for each rotation of the seats
+		if i%len(config.Entrants) == 0 {
+			seed = randomizer.Int63()
+		}
+
+		seats := make([]int, config.PlayersPerGame)
+		for j := range seats {
+			seats[j] = (i + j) % len(config.Entrants)
+		}
+
+		g := NewGame(append(parameters, NewGameParameter(Seed, int(seed)))...)
+		g.SetOutput(io.Discard)
+		for j, entrant := range seats {
+			controller, _ := NewRegisteredController(config.Entrants[entrant], g.NewRandomizer())
+			g.SetController(j, controller)
+		}
+
+		recordGame(entries, seats, g.Run(1)[0])
+	}
+
+	return entries, nil
+}
+
+func recordGame(entries []TournamentEntry, seats []int, result GameResult) {
+
+	winShares := make([]float64, len(seats))
+	for j, player := range result.Players {
+		if result.IsWinner(player.Id) {
+			winShares[j] = 1 / float64(len(result.Winners))
+		}
+	}
+
+	for j, entrant := range seats {
+		entry := &entries[entrant]
+		entry.Games++
+		entry.Wins += winShares[j]
+		entry.Rounds += result.Rounds
+		if result.Players[j].Dead {
+			entry.Deaths++
+		}
+		if result.EndReason == RoundLimitReached {
+			entry.Capped++
+		}
+	}
+
+	//Every game counts as a match between each pair of seats
+	ratingChanges := make([]float64, len(seats))
+	factor := ratingFactor / float64(len(seats)-1)
+	for a := range seats {
+		for b := range seats {
+			if a == b {
+				continue
+			}
+			outcome := 0.5
+			scoreA, scoreB := result.Players[a].Score, result.Players[b].Score
+			switch {
+			case winShares[a] > winShares[b], winShares[a] == winShares[b] && scoreA > scoreB:
+				outcome = 1
+			case winShares[a] < winShares[b], winShares[a] == winShares[b] && scoreA < scoreB:
+				outcome = 0
+			}
+			expected := 1 / (1 + math.Pow(10, (entries[seats[b]].Rating-entries[seats[a]].Rating)/400))
+			ratingChanges[a] += factor * (outcome - expected)
+		}
+	}
+
+	for j, entrant := range seats {
+		entries[entrant].Rating += ratingChanges[j]
+	}
+}
+
+func PrintTournament(out io.Writer, entries []TournamentEntry) {
+
+	sorted := slices.Clone(entries)
+	slices.SortStableFunc(sorted, func(a, b TournamentEntry) int {
+		return cmp.Compare(b.Rating, a.Rating)
+	})
+
+	fmt.Fprintf(out, "%-4s %-16s %6s %8s %11s %8s %6s %7s\n", "#", "Controller", "Games", "Win %", "Avg rounds", "Death %", "Capped", "Elo")
+	for i, entry := range sorted {
+		games := float64(max(entry.Games, 1))
+		fmt.Fprintf(out, "%-4d %-16s %6d %7.1f%% %11.1f %7.1f%% %6d %7.0f\n",
+			i+1,
+			entry.Name,
+			entry.Games,
+			100*entry.Wins/games,
+			float64(entry.Rounds)/games,
+			100*float64(entry.Deaths)/games,
+			entry.Capped,
+			entry.Rating,
+		)
+	}
+}
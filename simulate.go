@@ -0,0 +1,60 @@
+package main
+
+import (
+	"board-game-course/model"
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+	"time"
+)
+
+func simulate(args []string) {
+
+	flags := flag.NewFlagSet("simulate", flag.ExitOnError)
+	games := flags.Int("games", 1000, "number of games to play")
+	players := flags.Int("players", 0, "players per game, defaults to the number of controllers")
+	workers := flags.Int("workers", runtime.NumCPU(), "number of games played at the same time")
+	seed := flags.Int64("seed", time.Now().UnixNano(), "seed of the simulation")
+	output := flags.String("output", "simulation.jsonl", "file receiving one JSON result per game")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: simulate [flags] controller controller...\n")
+		fmt.Fprintf(flags.Output(), "Registered controllers: %s\n", strings.Join(model.RegisteredControllers(), ", "))
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() < 2 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	file, err := os.Create(*output)
+	if err != nil {
+		fmt.Printf("Cannot create output: %s\n", err)
+		os.Exit(1)
+	}
+	defer file.Close()
+
+	fmt.Printf("Simulation seed: %d\n", *seed)
+
+	err = model.Simulate(model.SimulationConfig{
+		Entrants:       flags.Args(),
+		Games:          *games,
+		PlayersPerGame: *players,
+		Workers:        *workers,
+		Seed:           *seed,
+	},
+		file,
+		os.Stderr,
+		NumberOfPanicCardsToActivateEffect,
+		NumberOfItemSlots,
+		NumberOfAmuletsToWin,
+		MaxRounds,
+	)
+	if err != nil {
+		fmt.Printf("Cannot run simulation: %s\n", err)
+		os.Exit(1)
+	}
+}
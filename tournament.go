@@ -0,0 +1,49 @@
+package main
+
+import (
+	"board-game-course/model"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+func tournament(args []string) {
+
+	flags := flag.NewFlagSet("tournament", flag.ExitOnError)
+	games := flags.Int("games", 100, "number of games to play")
+	players := flags.Int("players", 0, "players per game, defaults to the number of controllers")
+	seed := flags.Int64("seed", time.Now().UnixNano(), "seed of the tournament")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: tournament [flags] controller controller...\n")
+		fmt.Fprintf(flags.Output(), "Registered controllers: %s\n", strings.Join(model.RegisteredControllers(), ", "))
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() < 2 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	fmt.Printf("Tournament seed: %d\n", *seed)
+
+	entries, err := model.RunTournament(model.TournamentConfig{
+		Entrants:       flags.Args(),
+		Games:          *games,
+		PlayersPerGame: *players,
+		Seed:           *seed,
+	},
+		NumberOfPanicCardsToActivateEffect,
+		NumberOfItemSlots,
+		NumberOfAmuletsToWin,
+		MaxRounds,
+	)
+	if err != nil {
+		fmt.Printf("Cannot run tournament: %s\n", err)
+		os.Exit(1)
+	}
+
+	model.PrintTournament(os.Stdout, entries)
+}